@@ -0,0 +1,104 @@
+package lucene
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/grindlemire/go-lucene/pkg/driver"
+)
+
+func TestBigQuerySQLEndToEnd(t *testing.T) {
+	type tc struct {
+		input        string
+		want         string
+		defaultField string
+		err          string
+	}
+
+	tcs := map[string]tc{
+		"basic_equal": {
+			input: "a:b",
+			want:  "`a` = 'b'",
+		},
+		"struct_path": {
+			input: "user.city:Paris",
+			want:  "`user`.`city` = 'Paris'",
+		},
+		"basic_wild_equal_with_*": {
+			input: "a:b*",
+			want:  "`a` LIKE 'b%'",
+		},
+		"standalone_wild": {
+			input: "a:*",
+			want:  "`a` IS NOT NULL",
+		},
+		"regexp": {
+			input: `a:/^b\.c/`,
+			want:  "REGEXP_CONTAINS(`a`, r'^b\\.c')",
+		},
+		"basic_inclusive_range": {
+			input: "a:[1 TO 5]",
+			want:  "`a` >= 1 AND `a` <= 5",
+		},
+		"in_list": {
+			input: "a:(b OR c)",
+			want:  "`a` IN ('b', 'c')",
+		},
+		"null": {
+			input: "a:null",
+			want:  "`a` IS NULL",
+		},
+		"must_not": {
+			input: "a:b -c:d",
+			want:  "(`a` = 'b') AND (NOT(`c` = 'd'))",
+		},
+		"default_field": {
+			input:        "foo",
+			defaultField: "a",
+			want:         "`a` = 'foo'",
+		},
+		"fuzzy": {
+			input: "a:foo~2",
+			want:  "EDIT_DISTANCE(`a`, 'foo') <= 2",
+		},
+		"empty_path_segment_errors": {
+			input: "a..b:c",
+			err:   "column name has an empty path segment",
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			got, err := ToBigQuery(tc.input, WithDefaultField(tc.defaultField))
+			if err != nil {
+				if tc.err != "" && strings.Contains(err.Error(), tc.err) {
+					return
+				}
+				t.Fatalf("unexpected error rendering expression: %v", err)
+			}
+
+			if tc.err != "" {
+				t.Fatalf("\nexpected error [%s]\ngot: %s", tc.err, got)
+			}
+
+			if got != tc.want {
+				t.Fatalf("\nwant %s\ngot  %s\n", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestBigQueryParameterizedSQLEndToEnd(t *testing.T) {
+	got, params, err := ToParameterizedBigQuery("a:b AND c:[1 TO 5]")
+	if err != nil {
+		t.Fatalf("unexpected error rendering expression: %v", err)
+	}
+	if want := "(`a` = @p1) AND (`c` >= @p2 AND `c` <= @p3)"; got != want {
+		t.Fatalf("\nwant %s\ngot  %s\n", want, got)
+	}
+	want := []driver.BigQueryParam{{Name: "p1", Value: "b"}, {Name: "p2", Value: 1}, {Name: "p3", Value: 5}}
+	if !reflect.DeepEqual(params, want) {
+		t.Fatalf("expected params %v, got %v", want, params)
+	}
+}
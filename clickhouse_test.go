@@ -0,0 +1,139 @@
+package lucene
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestClickHouseSQLEndToEnd(t *testing.T) {
+	type tc struct {
+		input        string
+		want         string
+		defaultField string
+		err          string
+	}
+
+	tcs := map[string]tc{
+		"basic_equal": {
+			input: "a:b",
+			want:  "`a` = 'b'",
+		},
+		"basic_equal_with_number": {
+			input: "a:5",
+			want:  "`a` = 5",
+		},
+		"basic_wild_equal_with_*": {
+			input: "a:b*",
+			want:  "`a` LIKE 'b%'",
+		},
+		"wild_with_like_metacharacters": {
+			input: "a:100%_*",
+			want:  "`a` LIKE '100\\\\%\\\\_%'",
+		},
+		"standalone_wild": {
+			input: "a:*",
+			want:  "isNotNull(`a`)",
+		},
+		"regexp": {
+			input: "a:/b.*/",
+			want:  "match(`a`, 'b.*')",
+		},
+		"basic_inclusive_range": {
+			input: "a:[1 TO 5]",
+			want:  "`a` >= 1 AND `a` <= 5",
+		},
+		"in_list": {
+			input: "a:(b OR c)",
+			want:  "`a` IN ('b', 'c')",
+		},
+		"null": {
+			input: "a:null",
+			want:  "`a` IS NULL",
+		},
+		"must_not": {
+			input: "a:b -c:d",
+			want:  "(`a` = 'b') AND (NOT(`c` = 'd'))",
+		},
+		"phrase_with_quote": {
+			input: `a:"it's here"`,
+			want:  "`a` = 'it\\'s here'",
+		},
+		"default_field": {
+			input:        "foo",
+			defaultField: "a",
+			want:         "`a` = 'foo'",
+		},
+		"fuzzy": {
+			input: "a:foo~2",
+			want:  "editDistance(`a`, 'foo') <= 2",
+		},
+		"column_with_backtick_errors": {
+			input: "a\\`b:c",
+			err:   "column name contains a backtick",
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			got, err := ToClickHouse(tc.input, WithDefaultField(tc.defaultField))
+			if err != nil {
+				if tc.err != "" && strings.Contains(err.Error(), tc.err) {
+					return
+				}
+				t.Fatalf("unexpected error rendering expression: %v", err)
+			}
+
+			if tc.err != "" {
+				t.Fatalf("\nexpected error [%s]\ngot: %s", tc.err, got)
+			}
+
+			if got != tc.want {
+				t.Fatalf("\nwant %s\ngot  %s\n", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestClickHouseParameterizedSQLEndToEnd(t *testing.T) {
+	type tc struct {
+		input      string
+		wantStr    string
+		wantParams []any
+	}
+
+	tcs := map[string]tc{
+		"basic_equal": {
+			input:      "a:b",
+			wantStr:    "`a` = {p1:String}",
+			wantParams: []any{"b"},
+		},
+		"typed_in_order": {
+			input:      "a:b AND c:[1 TO 5.5] AND d:/x+/",
+			wantStr:    "((`a` = {p1:String}) AND (`c` >= {p2:Int64} AND `c` <= {p3:Float64})) AND (match(`d`, {p4:String}))",
+			wantParams: []any{"b", 1, 5.5, "x+"},
+		},
+		"in_list": {
+			input:      "a:(x OR y)",
+			wantStr:    "`a` IN ({p1:String}, {p2:String})",
+			wantParams: []any{"x", "y"},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			gotStr, gotParams, err := ToParameterizedClickHouse(tc.input)
+			if err != nil {
+				t.Fatalf("unexpected error rendering expression: %v", err)
+			}
+
+			if gotStr != tc.wantStr {
+				t.Fatalf("\nwant %s\ngot  %s\n", tc.wantStr, gotStr)
+			}
+
+			if !reflect.DeepEqual(gotParams, tc.wantParams) {
+				t.Fatalf("expected params %v, got %v", tc.wantParams, gotParams)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package lucene
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// WithClock sets the clock that resolves now in date math such as timestamp:[now-7d TO now].
+// It defaults to time.Now in UTC and is mostly useful to pin the current time in tests.
+func WithClock(now func() time.Time) Opt {
+	return func(p *parser) {
+		p.now = now
+	}
+}
+
+func (p *parser) clock() time.Time {
+	if p.now == nil {
+		return time.Now().UTC()
+	}
+	return p.now()
+}
+
+// dateMathPattern matches Elasticsearch style date math: an anchor of now or a date followed
+// by ||, then any number of +N<unit>, -N<unit> or /<unit> (rounding) operations.
+var dateMathPattern = regexp.MustCompile(`^(now|[^|]+\|\|)((?:[+-]\d+[yMwdhHms]|/[yMwdhHms])*)$`)
+
+var dateMathOp = regexp.MustCompile(`([+-])(\d+)([yMwdhHms])|/([yMwdhHms])`)
+
+// datePrefix matches text starting with a yyyy-mm-dd date, e.g. 2024-03-01 or 2024-03-01T10:00.
+var datePrefix = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}`)
+
+// looksLikeDate reports whether text is written as a date literal or date math expression,
+// whether or not parseDate can resolve it, e.g. 2024-13-45 looks like a date but isn't one.
+func looksLikeDate(text string) bool {
+	return dateMathPattern.MatchString(text) || datePrefix.MatchString(text)
+}
+
+// parseDate resolves a date literal or date math expression. Rounding with /unit rounds down
+// to the start of the unit, or up to its last microsecond when roundUp is set, which is how
+// Elasticsearch treats the upper bound of an inclusive range and a > comparison.
+func (p *parser) parseDate(text string, roundUp bool) (time.Time, error) {
+	match := dateMathPattern.FindStringSubmatch(text)
+	if match == nil {
+		return parseTimestamp(text)
+	}
+
+	var t time.Time
+	if match[1] == "now" {
+		t = p.clock()
+	} else {
+		anchor, err := parseTimestamp(strings.TrimSuffix(match[1], "||"))
+		if err != nil {
+			return time.Time{}, err
+		}
+		t = anchor
+	}
+
+	for _, op := range dateMathOp.FindAllStringSubmatch(match[2], -1) {
+		if op[4] != "" {
+			t = roundDate(t, op[4], roundUp)
+			continue
+		}
+		n, err := strconv.Atoi(op[2])
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid date math %q: %w", text, err)
+		}
+		if op[1] == "-" {
+			n = -n
+		}
+		t = addDate(t, n, op[3])
+	}
+	return t, nil
+}
+
+func addDate(t time.Time, n int, unit string) time.Time {
+	switch unit {
+	case "y":
+		return t.AddDate(n, 0, 0)
+	case "M":
+		return t.AddDate(0, n, 0)
+	case "w":
+		return t.AddDate(0, 0, 7*n)
+	case "d":
+		return t.AddDate(0, 0, n)
+	case "h", "H":
+		return t.Add(time.Duration(n) * time.Hour)
+	case "m":
+		return t.Add(time.Duration(n) * time.Minute)
+	}
+	return t.Add(time.Duration(n) * time.Second)
+}
+
+func roundDate(t time.Time, unit string, roundUp bool) time.Time {
+	y, mo, d := t.Date()
+	loc := t.Location()
+
+	var start time.Time
+	switch unit {
+	case "y":
+		start = time.Date(y, time.January, 1, 0, 0, 0, 0, loc)
+	case "M":
+		start = time.Date(y, mo, 1, 0, 0, 0, 0, loc)
+	case "w":
+		// weeks start on monday
+		offset := (int(t.Weekday()) + 6) % 7
+		start = time.Date(y, mo, d-offset, 0, 0, 0, 0, loc)
+	case "d":
+		start = time.Date(y, mo, d, 0, 0, 0, 0, loc)
+	default:
+		precision := time.Second
+		switch unit {
+		case "h", "H":
+			precision = time.Hour
+		case "m":
+			precision = time.Minute
+		}
+		start = t.Truncate(precision)
+		if roundUp {
+			return start.Add(precision - time.Microsecond)
+		}
+		return start
+	}
+
+	if roundUp {
+		return addDate(start, 1, unit).Add(-time.Microsecond)
+	}
+	return start
+}
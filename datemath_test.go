@@ -0,0 +1,232 @@
+package lucene
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDateMath(t *testing.T) {
+	// a wednesday
+	now := time.Date(2024, 3, 13, 15, 4, 5, 0, time.UTC)
+	clock := WithClock(func() time.Time { return now })
+	day := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, time.UTC) }
+	endOfDay := func(y int, m time.Month, d int) time.Time { return day(y, m, d+1).Add(-time.Microsecond) }
+
+	type tc struct {
+		input      string
+		wantStr    string
+		wantParams []any
+	}
+
+	tcs := map[string]tc{
+		"now": {
+			input:      "t:<now",
+			wantStr:    `"t" < $1`,
+			wantParams: []any{now},
+		},
+		"range_relative_to_now": {
+			input:      "t:[now-7d TO now]",
+			wantStr:    `"t" BETWEEN $1 AND $2`,
+			wantParams: []any{now.AddDate(0, 0, -7), now},
+		},
+		"add_hours": {
+			input:      "t:>now+1h",
+			wantStr:    `"t" > $1`,
+			wantParams: []any{now.Add(time.Hour)},
+		},
+		"chained_math": {
+			input:      "t:>=now-1M-2d+30m",
+			wantStr:    `"t" >= $1`,
+			wantParams: []any{time.Date(2024, 2, 11, 15, 34, 5, 0, time.UTC)},
+		},
+		"inclusive_range_rounds_upper_bound_up": {
+			input:      "t:[now-1d/d TO now/d]",
+			wantStr:    `"t" BETWEEN $1 AND $2`,
+			wantParams: []any{day(2024, 3, 12), endOfDay(2024, 3, 13)},
+		},
+		"exclusive_range_rounds_lower_bound_up": {
+			input:      "t:{now-1d/d TO now/d}",
+			wantStr:    `"t" > $1 AND "t" < $2`,
+			wantParams: []any{endOfDay(2024, 3, 12), day(2024, 3, 13)},
+		},
+		"greater_rounds_up": {
+			input:      "t:>now/d",
+			wantStr:    `"t" > $1`,
+			wantParams: []any{endOfDay(2024, 3, 13)},
+		},
+		"greater_eq_rounds_down": {
+			input:      "t:>=now/d",
+			wantStr:    `"t" >= $1`,
+			wantParams: []any{day(2024, 3, 13)},
+		},
+		"less_eq_rounds_up": {
+			input:      "t:<=now/M",
+			wantStr:    `"t" <= $1`,
+			wantParams: []any{endOfDay(2024, 3, 31)},
+		},
+		"round_to_week": {
+			input:      "t:>=now/w",
+			wantStr:    `"t" >= $1`,
+			wantParams: []any{day(2024, 3, 11)},
+		},
+		"round_to_year": {
+			input:      "t:>=now/y",
+			wantStr:    `"t" >= $1`,
+			wantParams: []any{day(2024, 1, 1)},
+		},
+		"round_to_hour": {
+			input:      "t:<now/h",
+			wantStr:    `"t" < $1`,
+			wantParams: []any{time.Date(2024, 3, 13, 15, 0, 0, 0, time.UTC)},
+		},
+		"date_literal": {
+			input:      "created:>=2024-03-01",
+			wantStr:    `"created" >= $1`,
+			wantParams: []any{day(2024, 3, 1)},
+		},
+		"date_literal_range": {
+			input:      "created:[2024-01-01 TO 2024-02-01}",
+			wantStr:    `"created" > $1 AND "created" < $2`,
+			wantParams: []any{day(2024, 1, 1), day(2024, 2, 1)},
+		},
+		"quoted_timestamp": {
+			input:      `created:<"2024-03-01T10:30:00+02:00"`,
+			wantStr:    `"created" < $1`,
+			wantParams: []any{time.Date(2024, 3, 1, 10, 30, 0, 0, time.FixedZone("", 2*60*60))},
+		},
+		"anchored_date_math": {
+			input:      "created:<2024-01-31||+1M/M",
+			wantStr:    `"created" < $1`,
+			wantParams: []any{day(2024, 3, 1)},
+		},
+		"open_range": {
+			input:      "t:[now-30d TO *]",
+			wantStr:    `"t" >= $1`,
+			wantParams: []any{now.AddDate(0, 0, -30)},
+		},
+		"equality_is_not_a_date": {
+			input:      "t:2024-03-01",
+			wantStr:    `"t" = $1`,
+			wantParams: []any{"2024-03-01"},
+		},
+		"non_date_words_are_untouched": {
+			input:      "t:[nowhere TO zebra]",
+			wantStr:    `"t" BETWEEN $1 AND $2`,
+			wantParams: []any{"nowhere", "zebra"},
+		},
+		"string_schema_field_is_untouched": {
+			input:      "name:>now",
+			wantStr:    `"name" > $1`,
+			wantParams: []any{"now"},
+		},
+		"timestamp_schema_field_accepts_date_math": {
+			input:      "created:now/d",
+			wantStr:    `"created" = $1`,
+			wantParams: []any{day(2024, 3, 13)},
+		},
+	}
+
+	schema := WithSchema(Schema{"name": StringField, "created": TimestampField})
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			got, params, err := ToParameterizedPostgres(tc.input, clock, schema)
+			if err != nil {
+				t.Fatalf("unexpected error rendering expression: %v", err)
+			}
+			if got != tc.wantStr {
+				t.Fatalf("\nwant %s\ngot  %s", tc.wantStr, got)
+			}
+			if !reflect.DeepEqual(params, tc.wantParams) {
+				t.Fatalf("\nwant params %#v\ngot  params %#v", tc.wantParams, params)
+			}
+		})
+	}
+}
+
+func TestDateMathInvalidTimestamp(t *testing.T) {
+	_, err := Parse("created:>now-1x", WithSchema(Schema{"created": TimestampField}))
+	var typeErr *FieldTypeError
+	if !errors.As(err, &typeErr) {
+		t.Fatalf("expected a *FieldTypeError, got: %v", err)
+	}
+	if typeErr.Value != "now-1x" {
+		t.Fatalf("expected error for %q, got %q", "now-1x", typeErr.Value)
+	}
+}
+
+func TestDateMathInvalidUntypedDate(t *testing.T) {
+	tcs := map[string]string{
+		"range_bound":      "a:[2024-13-45 TO *]",
+		"comparison":       "a:>2024-02-30",
+		"date_math_anchor": "a:<2024-13-01||+1d",
+	}
+
+	for name, input := range tcs {
+		t.Run(name, func(t *testing.T) {
+			_, err := Parse(input)
+			var typeErr *FieldTypeError
+			if !errors.As(err, &typeErr) {
+				t.Fatalf("expected a *FieldTypeError, got: %v", err)
+			}
+			if typeErr.Field != "a" || typeErr.Type.String() != "timestamp" {
+				t.Fatalf("expected a timestamp error for a, got: %v", typeErr)
+			}
+		})
+	}
+}
+
+func TestDateMathDialects(t *testing.T) {
+	now := time.Date(2024, 3, 13, 15, 4, 5, 0, time.UTC)
+	clock := WithClock(func() time.Time { return now })
+	input := "t:[now-1d/d TO now]"
+
+	type tc struct {
+		render     func() (string, error)
+		want       string
+		renderArgs func() (string, []any, error)
+		wantArgs   []any
+	}
+
+	tcs := map[string]tc{
+		"postgres": {
+			render:     func() (string, error) { return ToPostgres(input, clock) },
+			want:       `"t" BETWEEN '2024-03-12T00:00:00Z'::timestamptz AND '2024-03-13T15:04:05Z'::timestamptz`,
+			renderArgs: func() (string, []any, error) { return ToParameterizedPostgres(input, clock) },
+			wantArgs:   []any{time.Date(2024, 3, 12, 0, 0, 0, 0, time.UTC), now},
+		},
+		"mysql": {
+			render:     func() (string, error) { return ToMySQL(input, clock) },
+			want:       "`t` BETWEEN '2024-03-12 00:00:00' AND '2024-03-13 15:04:05'",
+			renderArgs: func() (string, []any, error) { return ToParameterizedMySQL(input, clock) },
+			wantArgs:   []any{time.Date(2024, 3, 12, 0, 0, 0, 0, time.UTC), now},
+		},
+		"sqlite": {
+			render:     func() (string, error) { return ToSQLite(input, clock) },
+			want:       `"t" BETWEEN '2024-03-12 00:00:00' AND '2024-03-13 15:04:05'`,
+			renderArgs: func() (string, []any, error) { return ToParameterizedSQLite(input, clock) },
+			wantArgs:   []any{"2024-03-12 00:00:00", "2024-03-13 15:04:05"},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			got, err := tc.render()
+			if err != nil {
+				t.Fatalf("unexpected error rendering expression: %v", err)
+			}
+			if got != tc.want {
+				t.Fatalf("\nwant %s\ngot  %s", tc.want, got)
+			}
+
+			_, params, err := tc.renderArgs()
+			if err != nil {
+				t.Fatalf("unexpected error rendering expression: %v", err)
+			}
+			if !reflect.DeepEqual(params, tc.wantArgs) {
+				t.Fatalf("\nwant params %#v\ngot  params %#v", tc.wantArgs, params)
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package lucene
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestElasticsearchEndToEnd(t *testing.T) {
+	type tc struct {
+		input        string
+		want         string
+		defaultField string
+		err          string
+	}
+
+	tcs := map[string]tc{
+		"basic_equal": {
+			input: "a:b",
+			want:  `{"query":{"term":{"a":{"value":"b"}}}}`,
+		},
+		"basic_equal_with_number": {
+			input: "a:5",
+			want:  `{"query":{"term":{"a":{"value":5}}}}`,
+		},
+		"phrase": {
+			input: `a:"foo bar"`,
+			want:  `{"query":{"match_phrase":{"a":{"query":"foo bar"}}}}`,
+		},
+		"bare_term": {
+			input: `foo`,
+			want:  `{"query":{"multi_match":{"query":"foo"}}}`,
+		},
+		"bare_term_with_default_field": {
+			input:        `foo`,
+			defaultField: "a",
+			want:         `{"query":{"term":{"a":{"value":"foo"}}}}`,
+		},
+		"and_flattens": {
+			input: "a:1 AND b:2 AND c:3",
+			want:  `{"query":{"bool":{"must":[{"term":{"a":{"value":1}}},{"term":{"b":{"value":2}}},{"term":{"c":{"value":3}}}]}}}`,
+		},
+		"or": {
+			input: "a:1 OR b:2",
+			want:  `{"query":{"bool":{"minimum_should_match":1,"should":[{"term":{"a":{"value":1}}},{"term":{"b":{"value":2}}}]}}}`,
+		},
+		"not": {
+			input: "NOT a:1",
+			want:  `{"query":{"bool":{"must_not":[{"term":{"a":{"value":1}}}]}}}`,
+		},
+		"must_and_must_not": {
+			input: "+a:1 -b:2 c:3",
+			want:  `{"query":{"bool":{"must":[{"term":{"a":{"value":1}}},{"term":{"c":{"value":3}}}],"must_not":[{"term":{"b":{"value":2}}}]}}}`,
+		},
+		"wildcard": {
+			input: "a:fo*",
+			want:  `{"query":{"wildcard":{"a":{"value":"fo*"}}}}`,
+		},
+		"standalone_wildcard": {
+			input: "a:*",
+			want:  `{"query":{"exists":{"field":"a"}}}`,
+		},
+		"regexp": {
+			input: "a:/fo+/",
+			want:  `{"query":{"regexp":{"a":{"value":"fo+"}}}}`,
+		},
+		"inclusive_range": {
+			input: "a:[1 TO 5]",
+			want:  `{"query":{"range":{"a":{"gte":1,"lte":5}}}}`,
+		},
+		"exclusive_range": {
+			input: "a:{foo TO bar}",
+			want:  `{"query":{"range":{"a":{"gt":"foo","lt":"bar"}}}}`,
+		},
+		"open_range": {
+			input: "a:[* TO 5]",
+			want:  `{"query":{"range":{"a":{"lte":5}}}}`,
+		},
+		"unbounded_range": {
+			input: "a:[* TO *]",
+			want:  `{"query":{"exists":{"field":"a"}}}`,
+		},
+		"comparison": {
+			input: "a:>=5",
+			want:  `{"query":{"range":{"a":{"gte":5}}}}`,
+		},
+		"in": {
+			input: "a:(x OR y)",
+			want:  `{"query":{"terms":{"a":["x","y"]}}}`,
+		},
+		"in_with_null": {
+			input: "a:(x OR null)",
+			want:  `{"query":{"bool":{"minimum_should_match":1,"should":[{"terms":{"a":["x"]}},{"bool":{"must_not":[{"exists":{"field":"a"}}]}}]}}}`,
+		},
+		"null": {
+			input: "a:null",
+			want:  `{"query":{"bool":{"must_not":[{"exists":{"field":"a"}}]}}}`,
+		},
+		"fuzzy": {
+			input: "a:foo~2",
+			want:  `{"query":{"fuzzy":{"a":{"fuzziness":2,"value":"foo"}}}}`,
+		},
+		"fuzzy_default_distance": {
+			input: "a:foo~",
+			want:  `{"query":{"fuzzy":{"a":{"fuzziness":1,"value":"foo"}}}}`,
+		},
+		"phrase_slop": {
+			input: `a:"foo bar"~3`,
+			want:  `{"query":{"match_phrase":{"a":{"query":"foo bar","slop":3}}}}`,
+		},
+		"phrase_slop_without_field": {
+			input: `"foo bar"~3`,
+			want:  `{"query":{"multi_match":{"query":"foo bar","slop":3,"type":"phrase"}}}`,
+		},
+		"phrase_prefix": {
+			input: `a:"foo ba"*`,
+			want:  `{"query":{"match_phrase_prefix":{"a":{"query":"foo ba"}}}}`,
+		},
+		"boost": {
+			input: "a:foo^2",
+			want:  `{"query":{"term":{"a":{"boost":2,"value":"foo"}}}}`,
+		},
+		"boost_group": {
+			input: "(a:1 OR b:2)^3",
+			want:  `{"query":{"bool":{"boost":3,"minimum_should_match":1,"should":[{"term":{"a":{"value":1}}},{"term":{"b":{"value":2}}}]}}}`,
+		},
+		"comparison_with_null_errors": {
+			input: "a:>null",
+			err:   "comparison operator GREATER cannot be used with null",
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			got, err := ToElasticsearch(tc.input, WithDefaultField(tc.defaultField))
+			if err != nil {
+				if tc.err != "" && strings.Contains(err.Error(), tc.err) {
+					return
+				}
+				t.Fatalf("unexpected error rendering expression: %v", err)
+			}
+
+			if tc.err != "" {
+				t.Fatalf("\nexpected error [%s]\ngot: %s", tc.err, got)
+			}
+
+			if got != tc.want {
+				t.Fatalf("\nwant %s\ngot  %s\n", tc.want, got)
+			}
+		})
+	}
+}
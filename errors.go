@@ -0,0 +1,287 @@
+package lucene
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/grindlemire/go-lucene/internal/lex"
+	"github.com/grindlemire/go-lucene/pkg/lucene/expr"
+)
+
+// ParseError describes a syntax error in a lucene query. It carries the position of the
+// offending token so callers can point at the exact spot in the input.
+type ParseError struct {
+	// Offset is the byte offset of the offending token in the input.
+	Offset int
+	// Line and Column are the 1-based position of the offending token. Column counts runes,
+	// not bytes.
+	Line   int
+	Column int
+	// Token is the offending token as it appears in the input. It is empty when the error
+	// is at the end of the input.
+	Token string
+	// Expected is a short hint of what the parser wanted instead, e.g. `")"` for a missing
+	// token or `expression` for a missing operand. It is empty when there is no single
+	// sensible suggestion.
+	Expected string
+	// Msg describes the problem.
+	Msg string
+}
+
+func (e *ParseError) Error() string {
+	if e.Expected != "" {
+		return fmt.Sprintf("parse error at line %d, column %d: %s, expected %s", e.Line, e.Column, e.Msg, e.Expected)
+	}
+	return fmt.Sprintf("parse error at line %d, column %d: %s", e.Line, e.Column, e.Msg)
+}
+
+// newParseError builds a ParseError for the token at offset in the input.
+func newParseError(input string, offset int, token, msg, expected string) *ParseError {
+	if offset > len(input) {
+		offset = len(input)
+	}
+
+	line := 1 + strings.Count(input[:offset], "\n")
+	lineStart := strings.LastIndexByte(input[:offset], '\n') + 1
+
+	return &ParseError{
+		Offset:   offset,
+		Line:     line,
+		Column:   utf8.RuneCountInString(input[lineStart:offset]) + 1,
+		Token:    token,
+		Expected: expected,
+		Msg:      msg,
+	}
+}
+
+// lexError converts a TErr token from the lexer into a ParseError.
+func (p *parser) lexError(tok lex.Token) *ParseError {
+	rest := p.input[min(tok.Pos(), len(p.input)):]
+
+	// unterminated phrases and regexps run to the end of the input so the whole
+	// rest is the offending token. Anything else is a single unexpected character.
+	token := rest
+	expected := ""
+	if r, width := utf8.DecodeRuneInString(rest); r == '"' || r == '\'' || r == '/' {
+		expected = fmt.Sprintf("closing %q", r)
+	} else if width > 0 {
+		token = rest[:width]
+	}
+
+	return newParseError(p.input, tok.Pos(), token, tok.Val, expected)
+}
+
+// syntaxError inspects a stack that could not be reduced any further and blames the
+// token most likely responsible for it.
+func (p *parser) syntaxError(stack []any) *ParseError {
+	at := func(tok lex.Token, msg, expected string) *ParseError {
+		return newParseError(p.input, tok.Pos(), tok.Val, msg, expected)
+	}
+
+	// operators missing one of their operands
+	for i, s := range stack {
+		tok, ok := s.(lex.Token)
+		if !ok {
+			continue
+		}
+		if needsLeftOperand(tok) && !hasLeftOperand(stack, i) {
+			return at(tok, fmt.Sprintf("missing expression before %q", tok.Val), "expression")
+		}
+		if needsRightOperand(tok) && !hasRightOperand(stack, i) {
+			return at(tok, fmt.Sprintf("missing expression after %q", tok.Val), "expression")
+		}
+	}
+
+	// groupings and ranges that are empty, unbalanced, or malformed
+	for i, s := range stack {
+		tok, ok := s.(lex.Token)
+		if !ok {
+			continue
+		}
+		switch tok.Typ {
+		case lex.TLParen:
+			closeIdx := indexOfToken(stack[i+1:], lex.TRParen)
+			if closeIdx < 0 {
+				return at(tok, `unclosed "("`, `")"`)
+			}
+			if closeIdx == 0 {
+				return at(stack[i+1].(lex.Token), "empty group", "expression")
+			}
+		case lex.TLSquare, lex.TLCurly:
+			if indexOfToken(stack[i+1:], lex.TRSquare) < 0 && indexOfToken(stack[i+1:], lex.TRCurly) < 0 {
+				// the range stops being reduced at the first token it can't hold, e.g. a second TO,
+				// so it is only unclosed when that is the end of the input
+				next := p.lex.Peek()
+				if next.Typ == lex.TEOF {
+					return at(tok, fmt.Sprintf("unclosed range %q", tok.Val), closingRange(tok))
+				}
+				return at(next, fmt.Sprintf("unexpected %q in range", next.Val), closingRange(tok))
+			}
+			return at(tok, "malformed range", `"[min TO max]"`)
+		case lex.TRParen, lex.TRSquare, lex.TRCurly:
+			return at(tok, fmt.Sprintf("unexpected %q", tok.Val), "")
+		}
+	}
+
+	next := p.lex.Peek()
+	if next.Typ == lex.TEOF {
+		return newParseError(p.input, len(p.input), "", "unexpected end of input", "")
+	}
+	return at(next, fmt.Sprintf("unexpected %q", next.Val), "")
+}
+
+func closingRange(tok lex.Token) string {
+	if tok.Typ == lex.TLCurly {
+		return `"}"`
+	}
+	return `"]"`
+}
+
+// needsLeftOperand reports whether the token is an infix or postfix operator.
+func needsLeftOperand(tok lex.Token) bool {
+	switch tok.Typ {
+	case lex.TAnd, lex.TOr, lex.TTO, lex.TColon, lex.TEqual, lex.TGreater, lex.TLess, lex.TTilde, lex.TCarrot:
+		return true
+	}
+	return false
+}
+
+// needsRightOperand reports whether the token is an infix or prefix operator.
+func needsRightOperand(tok lex.Token) bool {
+	switch tok.Typ {
+	case lex.TAnd, lex.TOr, lex.TTO, lex.TColon, lex.TEqual, lex.TGreater, lex.TLess, lex.TNot, lex.TPlus, lex.TMinus:
+		return true
+	}
+	return false
+}
+
+func hasLeftOperand(stack []any, i int) bool {
+	if i == 0 {
+		return false
+	}
+	prev, isTok := stack[i-1].(lex.Token)
+	if !isTok {
+		return true
+	}
+
+	curr := stack[i].(lex.Token)
+	switch {
+	// the comparison operators are lexed as separate tokens, e.g. a:>=1 is a : > = 1
+	case (curr.Typ == lex.TGreater || curr.Typ == lex.TLess) && prev.Typ == lex.TColon:
+		return true
+	case curr.Typ == lex.TEqual && (prev.Typ == lex.TGreater || prev.Typ == lex.TLess):
+		return true
+	}
+	return false
+}
+
+func hasRightOperand(stack []any, i int) bool {
+	if i == len(stack)-1 {
+		return false
+	}
+	next, isTok := stack[i+1].(lex.Token)
+	if !isTok {
+		return true
+	}
+
+	curr := stack[i].(lex.Token)
+	switch next.Typ {
+	case lex.TLParen, lex.TNot, lex.TPlus, lex.TMinus:
+		return true
+	case lex.TLSquare, lex.TLCurly, lex.TGreater, lex.TLess:
+		return curr.Typ == lex.TColon
+	case lex.TEqual:
+		return curr.Typ == lex.TGreater || curr.Typ == lex.TLess
+	}
+	return false
+}
+
+func indexOfToken(stack []any, typ lex.TokType) int {
+	for i, s := range stack {
+		if tok, ok := s.(lex.Token); ok && tok.Typ == typ {
+			return i
+		}
+	}
+	return -1
+}
+
+// span is the byte range of the input an expression was parsed from.
+type span struct {
+	start, end int
+}
+
+// trackSpan remembers the range of the input the expression was parsed from.
+func (p *parser) trackSpan(e *expr.Expression, sp span) {
+	if p.spans == nil {
+		p.spans = map[*expr.Expression]span{}
+	}
+	p.spans[e] = sp
+}
+
+// spanOf returns the range of the input covered by the tokens and expressions on a stack, and
+// false when none of them has a known position, e.g. an implicit AND.
+func (p *parser) spanOf(stack []any) (span, bool) {
+	out := span{}
+	found := false
+	for _, s := range stack {
+		var sp span
+		switch v := s.(type) {
+		case lex.Token:
+			// implicit ANDs are injected by the parser and don't appear in the input
+			if v.Pos() >= len(p.input) || !strings.HasPrefix(p.input[v.Pos():], v.Val) {
+				continue
+			}
+			sp = span{start: v.Pos(), end: v.Pos() + len(v.Val)}
+		case *expr.Expression:
+			var ok bool
+			if sp, ok = p.spans[v]; !ok {
+				continue
+			}
+		default:
+			continue
+		}
+		if !found {
+			out, found = sp, true
+			continue
+		}
+		out.start = min(out.start, sp.start)
+		out.end = max(out.end, sp.end)
+	}
+	return out, found
+}
+
+// validate validates the parsed expression, blaming the part of the input of the sub expression
+// that is invalid.
+func (p *parser) validate(e *expr.Expression) error {
+	err := expr.Validate(e)
+	if err == nil {
+		return nil
+	}
+
+	// descend to the innermost sub expression failing the same way
+	culprit := e
+	for {
+		next := (*expr.Expression)(nil)
+		for _, side := range []any{culprit.Left, culprit.Right} {
+			child, ok := side.(*expr.Expression)
+			if !ok || child == nil {
+				continue
+			}
+			if childErr := expr.Validate(child); childErr != nil && childErr.Error() == err.Error() {
+				next = child
+				break
+			}
+		}
+		if next == nil {
+			break
+		}
+		culprit = next
+	}
+
+	sp, ok := p.spans[culprit]
+	if !ok {
+		return newParseError(p.input, 0, "", err.Error(), "")
+	}
+	return newParseError(p.input, sp.start, p.input[sp.start:sp.end], err.Error(), "")
+}
@@ -0,0 +1,102 @@
+package lucene
+
+import (
+	"fmt"
+
+	"github.com/grindlemire/go-lucene/pkg/lucene/expr"
+)
+
+// UnknownFieldError is returned when a query references a field that is not in the
+// allow-list configured with WithAllowedFields or WithFieldMapping.
+type UnknownFieldError struct {
+	Field string
+}
+
+func (e *UnknownFieldError) Error() string {
+	return fmt.Sprintf("unknown field %q", e.Field)
+}
+
+// WithAllowedFields restricts the fields a query may reference. Any other field causes
+// Parse to fail with an *UnknownFieldError. It can be combined with WithFieldMapping, in
+// which case both the allowed fields and the mapped fields are accepted.
+func WithAllowedFields(fields ...string) Opt {
+	return func(p *parser) {
+		if p.allowedFields == nil {
+			p.allowedFields = map[string]struct{}{}
+		}
+		for _, f := range fields {
+			p.allowedFields[f] = struct{}{}
+		}
+	}
+}
+
+// WithFieldMapping maps the public field names a user types to the sql expression they
+// should render as. For example {"author": "users.display_name"} renders author:bob as
+// users.display_name = 'bob'. Mapped expressions are emitted verbatim (see expr.RawColumn)
+// so they must come from trusted configuration.
+//
+// Setting a mapping also turns on field restriction: a field that is neither mapped nor
+// passed to WithAllowedFields causes Parse to fail with an *UnknownFieldError.
+func WithFieldMapping(mapping map[string]string) Opt {
+	return func(p *parser) {
+		if p.allowedFields == nil {
+			p.allowedFields = map[string]struct{}{}
+		}
+		if p.fieldMapping == nil {
+			p.fieldMapping = map[string]string{}
+		}
+		for public, column := range mapping {
+			p.fieldMapping[public] = column
+		}
+	}
+}
+
+// restrictsFields reports whether the parser was configured with an allow-list.
+func (p *parser) restrictsFields() bool {
+	return p.allowedFields != nil
+}
+
+// resolveFields walks the parsed expression and checks every column against the
+// configured allow-list, replacing mapped fields with their sql expression.
+func (p *parser) resolveFields(in any) error {
+	switch v := in.(type) {
+	case *expr.Expression:
+		if v == nil {
+			return nil
+		}
+		if col, ok := v.Left.(expr.Column); ok && v.Op == expr.Literal {
+			resolved, err := p.resolveField(col)
+			if err != nil {
+				return err
+			}
+			v.Left = resolved
+			return nil
+		}
+		if err := p.resolveFields(v.Left); err != nil {
+			return err
+		}
+		return p.resolveFields(v.Right)
+	case []*expr.Expression:
+		for _, e := range v {
+			if err := p.resolveFields(e); err != nil {
+				return err
+			}
+		}
+	case *expr.RangeBoundary:
+		if err := p.resolveFields(v.Min); err != nil {
+			return err
+		}
+		return p.resolveFields(v.Max)
+	}
+	return nil
+}
+
+func (p *parser) resolveField(col expr.Column) (any, error) {
+	if mapped, ok := p.fieldMapping[string(col)]; ok {
+		return expr.RawColumn(mapped), nil
+	}
+	if _, ok := p.allowedFields[string(col)]; ok {
+		return col, nil
+	}
+	return nil, &UnknownFieldError{Field: string(col)}
+}
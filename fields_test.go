@@ -0,0 +1,146 @@
+package lucene
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestFieldRestriction(t *testing.T) {
+	type tc struct {
+		input     string
+		opts      []Opt
+		wantField string
+	}
+
+	mapping := map[string]string{"author": "users.display_name"}
+
+	tcs := map[string]tc{
+		"allowed_field": {
+			input: "a:b AND c:[1 TO 5]",
+			opts:  []Opt{WithAllowedFields("a", "c")},
+		},
+		"unknown_field": {
+			input:     "a:b AND password_hash:*",
+			opts:      []Opt{WithAllowedFields("a")},
+			wantField: "password_hash",
+		},
+		"unknown_field_in_range": {
+			input:     "a:b OR z:[1 TO 5]",
+			opts:      []Opt{WithAllowedFields("a")},
+			wantField: "z",
+		},
+		"unknown_field_in_list": {
+			input:     "NOT z:(1 OR 2)",
+			opts:      []Opt{WithAllowedFields("a")},
+			wantField: "z",
+		},
+		"mapped_field_is_allowed": {
+			input: "author:bob",
+			opts:  []Opt{WithFieldMapping(mapping)},
+		},
+		"mapping_restricts_fields": {
+			input:     "author:bob AND a:b",
+			opts:      []Opt{WithFieldMapping(mapping)},
+			wantField: "a",
+		},
+		"mapping_and_allowed_combine": {
+			input: "author:bob AND a:b",
+			opts:  []Opt{WithFieldMapping(mapping), WithAllowedFields("a")},
+		},
+		"default_field_is_checked": {
+			input:     "bob",
+			opts:      []Opt{WithDefaultField("name"), WithAllowedFields("a")},
+			wantField: "name",
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			_, err := Parse(tc.input, tc.opts...)
+			if tc.wantField == "" {
+				if err != nil {
+					t.Fatalf("expected no error, got: %v", err)
+				}
+				return
+			}
+
+			var fieldErr *UnknownFieldError
+			if !errors.As(err, &fieldErr) {
+				t.Fatalf("expected an *UnknownFieldError, got: %v", err)
+			}
+			if fieldErr.Field != tc.wantField {
+				t.Fatalf("expected unknown field %q, got %q", tc.wantField, fieldErr.Field)
+			}
+		})
+	}
+}
+
+func TestFieldMappingRender(t *testing.T) {
+	type tc struct {
+		input      string
+		opts       []Opt
+		render     func(string, ...Opt) (string, []any, error)
+		wantStr    string
+		wantParams []any
+	}
+
+	mapping := map[string]string{
+		"author":  "users.display_name",
+		"created": "posts.created_at",
+	}
+
+	tcs := map[string]tc{
+		"postgres": {
+			input:      "author:bob AND created:[1 TO 5]",
+			opts:       []Opt{WithFieldMapping(mapping)},
+			render:     ToParameterizedPostgres,
+			wantStr:    `(users.display_name = $1) AND (posts.created_at >= $2 AND posts.created_at <= $3)`,
+			wantParams: []any{"bob", 1, 5},
+		},
+		"sqlite": {
+			input:      "author:bo* OR a:1",
+			opts:       []Opt{WithFieldMapping(mapping), WithAllowedFields("a")},
+			render:     ToParameterizedSQLite,
+			wantStr:    `(users.display_name GLOB ?) OR ("a" = ?)`,
+			wantParams: []any{"bo*", 1},
+		},
+		"mysql": {
+			input:      "author:(bob OR alice)",
+			opts:       []Opt{WithFieldMapping(mapping)},
+			render:     ToParameterizedMySQL,
+			wantStr:    "users.display_name IN (?, ?)",
+			wantParams: []any{"bob", "alice"},
+		},
+		"default_field": {
+			input:      "bob",
+			opts:       []Opt{WithDefaultField("author"), WithFieldMapping(mapping)},
+			render:     ToParameterizedPostgres,
+			wantStr:    `users.display_name = $1`,
+			wantParams: []any{"bob"},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			got, params, err := tc.render(tc.input, tc.opts...)
+			if err != nil {
+				t.Fatalf("unexpected error rendering expression: %v", err)
+			}
+			if got != tc.wantStr {
+				t.Fatalf("\nwant %s\ngot  %s", tc.wantStr, got)
+			}
+			if !reflect.DeepEqual(params, tc.wantParams) {
+				t.Fatalf("\nwant params %v\ngot  params %v", tc.wantParams, params)
+			}
+		})
+	}
+
+	got, err := ToMySQL("author:bob", WithFieldMapping(mapping))
+	if err != nil {
+		t.Fatalf("unexpected error rendering expression: %v", err)
+	}
+	if want := "users.display_name = 'bob'"; got != want {
+		t.Fatalf("\nwant %s\ngot  %s", want, got)
+	}
+}
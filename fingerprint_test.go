@@ -0,0 +1,67 @@
+package lucene
+
+import (
+	"testing"
+
+	"github.com/grindlemire/go-lucene/pkg/lucene/expr"
+)
+
+func TestFingerprintEndToEnd(t *testing.T) {
+	type tc struct {
+		a    string
+		b    string
+		same bool
+	}
+
+	tcs := map[string]tc{
+		"commutative_and":     {a: "a:1 AND b:2", b: "b:2 AND a:1", same: true},
+		"commutative_or":      {a: "a:1 OR b:2 OR c:3", b: "c:3 OR (a:1 OR b:2)", same: true},
+		"implicit_and":        {a: "a:1 b:2", b: "a:1 AND b:2", same: true},
+		"whitespace":          {a: "a:1   AND\tb:2", b: "a:1 AND b:2", same: true},
+		"redundant_parens":    {a: "((a:1)) AND (b:2)", b: "a:1 AND b:2", same: true},
+		"regrouped_and":       {a: "a:1 AND (b:2 AND c:3)", b: "(c:3 AND a:1) b:2", same: true},
+		"nested_order":        {a: "(a:1 OR b:2) AND NOT c:3", b: "NOT c:3 AND (b:2 OR a:1)", same: true},
+		"list_order":          {a: "a:(x OR y)", b: "a:(y OR x)", same: true},
+		"duplicate_clause":    {a: "a:1 AND a:1 AND b:2", b: "b:2 AND a:1", same: true},
+		"and_is_not_or":       {a: "a:1 AND b:2", b: "a:1 OR b:2"},
+		"grouping_matters":    {a: "a:1 OR (b:2 AND c:3)", b: "(a:1 OR b:2) AND c:3"},
+		"value_type":          {a: "a:1", b: `a:"1"`},
+		"range_inclusivity":   {a: "a:[1 TO 5]", b: "a:{1 TO 5}"},
+		"boost_power":         {a: "a:b^2", b: "a:b^3"},
+		"fuzzy_distance":      {a: "a:b~1", b: "a:b~2"},
+		"field_is_not_value":  {a: "a:b", b: "b:a"},
+		"negation_is_not_and": {a: "a:1 AND NOT b:2", b: "a:1 AND b:2"},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			a, err := Parse(tc.a)
+			if err != nil {
+				t.Fatalf("unexpected error parsing %s: %v", tc.a, err)
+			}
+			b, err := Parse(tc.b)
+			if err != nil {
+				t.Fatalf("unexpected error parsing %s: %v", tc.b, err)
+			}
+
+			fa, fb := expr.Fingerprint(a), expr.Fingerprint(b)
+			if (fa == fb) != tc.same {
+				t.Fatalf("expected same fingerprint to be %t for %s (%s) and %s (%s)", tc.same, tc.a, fa, tc.b, fb)
+			}
+			if !tc.same {
+				return
+			}
+			ca, err := expr.ToLucene(expr.Canonical(a))
+			if err != nil {
+				t.Fatalf("unexpected error serializing canonical form: %v", err)
+			}
+			cb, err := expr.ToLucene(expr.Canonical(b))
+			if err != nil {
+				t.Fatalf("unexpected error serializing canonical form: %v", err)
+			}
+			if ca != cb {
+				t.Fatalf("canonical forms differ:\n    %s\n    %s", ca, cb)
+			}
+		})
+	}
+}
@@ -16,6 +16,11 @@ type Token struct {
 	Val string  // the value of the item
 }
 
+// Pos returns the byte offset of the token in the input string.
+func (i Token) Pos() int {
+	return i.pos
+}
+
 // String is a string representation of a lex item
 func (i Token) String() string {
 	switch {
@@ -258,6 +263,7 @@ func lexPhrase(l *Lexer) tokenStateFn {
 		case r == ' ' || r == '\t' || r == '\r' || r == '\n':
 			// do nothing
 		case r == open:
+			l.lexPhrasePrefix()
 			return l.emit(TQuoted)
 		case r == eof:
 			return l.errorf("unterminated quote")
@@ -278,6 +284,7 @@ func lexRegexp(l *Lexer) tokenStateFn {
 		case r == ' ' || r == '\t' || r == '\r' || r == '\n':
 			// do nothing
 		case r == open:
+			l.lexRegexpFlags()
 			return l.emit(TRegexp)
 		case r == eof:
 			return l.errorf("unterminated regexp")
@@ -285,6 +292,34 @@ func lexRegexp(l *Lexer) tokenStateFn {
 	}
 }
 
+// lexPhrasePrefix consumes a * right after a phrase's closing quote, e.g. "quick fo"*, which
+// makes the phrase a prefix query. Like a regexp flag it only belongs to the phrase when nothing
+// else in the word follows it.
+func (l *Lexer) lexPhrasePrefix() {
+	rest := l.input[l.pos:]
+	if !strings.HasPrefix(rest, "*") {
+		return
+	}
+	if r, _ := utf8.DecodeRuneInString(rest[1:]); len(rest) > 1 && (isAlphaNumeric(r) || isWildcard(r)) {
+		return
+	}
+	l.next()
+}
+
+// lexRegexpFlags consumes the flags after a regexp's closing delimiter, e.g. the i in /abc/i.
+// i (case-insensitive) is the only flag, and it is only a flag when nothing else in the word
+// follows it, so /abc/in stays a regexp followed by the term n.
+func (l *Lexer) lexRegexpFlags() {
+	rest := l.input[l.pos:]
+	if !strings.HasPrefix(rest, "i") {
+		return
+	}
+	if r, _ := utf8.DecodeRuneInString(rest[1:]); len(rest) > 1 && isAlphaNumeric(r) {
+		return
+	}
+	l.next()
+}
+
 func lexWord(l *Lexer) tokenStateFn {
 loop:
 	for {
@@ -293,6 +328,10 @@ loop:
 			// do nothing
 		case isEscape(r):
 			l.next() // just ignore the next character
+		case r == '|' && l.peek() == '|' && isDateAnchor(l.input[l.start:l.pos-1]):
+			l.next() // date math anchored on a date, e.g. 2024-01-01||+1M
+		case (r == '+' || r == '/') && isDateMath(l.input[l.start:l.pos-1]):
+			// do nothing, + and / are date math operators here, e.g. now+1h/d
 		default:
 			l.backup()
 			break loop
@@ -402,6 +441,23 @@ func isLiteralChar(r rune) bool {
 	return r == '%' || r == '@' || r == '#' || r == '$'
 }
 
+// isDateMath reports whether a partially lexed word is the start of an Elasticsearch
+// style date math expression, in which + and / don't end the word. Words that only start
+// with now, such as nowhere, aren't date math.
+func isDateMath(word string) bool {
+	rest, found := strings.CutPrefix(word, "now")
+	if found && (rest == "" || strings.ContainsAny(rest[:1], "+-/|")) {
+		return true
+	}
+	return strings.Contains(word, "||")
+}
+
+// isDateAnchor reports whether a partially lexed word could be the date that a || date
+// math anchor follows.
+func isDateAnchor(word string) bool {
+	return len(word) > 0 && unicode.IsDigit(rune(word[0])) && !strings.Contains(word, "||")
+}
+
 // isSymbol checks whether the run is one of the reserved symbols
 func isSymbol(r rune) bool {
 	_, found := symbols[r]
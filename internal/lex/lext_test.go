@@ -153,6 +153,36 @@ func TestLex(t *testing.T) {
 				tok(TRegexp, `/.*example.com\/article\/.*/`),
 			},
 		},
+		"regexp_tokenized_with_flag": {
+			in: `/ab+c/i AND d`,
+			expected: []Token{
+				tok(TRegexp, "/ab+c/i"),
+				tok(TAnd, "AND"),
+				tok(TLiteral, "d"),
+			},
+		},
+		"regexp_flag_must_end_the_word": {
+			in: `/ab/in`,
+			expected: []Token{
+				tok(TRegexp, "/ab/"),
+				tok(TLiteral, "in"),
+			},
+		},
+		"phrase_prefix_tokenized": {
+			in: `"quick fo"* AND d`,
+			expected: []Token{
+				tok(TQuoted, `"quick fo"*`),
+				tok(TAnd, "AND"),
+				tok(TLiteral, "d"),
+			},
+		},
+		"phrase_prefix_must_end_the_word": {
+			in: `"ab"*c`,
+			expected: []Token{
+				tok(TQuoted, `"ab"`),
+				tok(TLiteral, "*c"),
+			},
+		},
 		"symbols_tokenized": {
 			in: `()[]{}:+-=><`,
 			expected: []Token{
@@ -341,6 +371,43 @@ func TestLex(t *testing.T) {
 				tok(TLiteral, "$variable"),
 			},
 		},
+		"date_math_in_range": {
+			in: "t:[now-7d/d TO now+1h]",
+			expected: []Token{
+				tok(TLiteral, "t"),
+				tok(TColon, ":"),
+				tok(TLSquare, "["),
+				tok(TLiteral, "now-7d/d"),
+				tok(TTO, "TO"),
+				tok(TLiteral, "now+1h"),
+				tok(TRSquare, "]"),
+			},
+		},
+		"date_math_anchored_on_date": {
+			in: "t:>2024-01-01||+1M/M",
+			expected: []Token{
+				tok(TLiteral, "t"),
+				tok(TColon, ":"),
+				tok(TGreater, ">"),
+				tok(TLiteral, "2024-01-01||+1M/M"),
+			},
+		},
+		"word_starting_with_now_is_not_date_math": {
+			in: "nowhere+b",
+			expected: []Token{
+				tok(TLiteral, "nowhere"),
+				tok(TPlus, "+"),
+				tok(TLiteral, "b"),
+			},
+		},
+		"plus_after_word_is_still_must": {
+			in: "a +b",
+			expected: []Token{
+				tok(TLiteral, "a"),
+				tok(TPlus, "+"),
+				tok(TLiteral, "b"),
+			},
+		},
 	}
 
 	for name, tc := range tcs {
@@ -0,0 +1,121 @@
+package lucene
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/grindlemire/go-lucene/pkg/lucene/expr"
+)
+
+func TestToLuceneRoundTrip(t *testing.T) {
+	type tc struct {
+		input string
+		want  string
+	}
+
+	tcs := map[string]tc{
+		"term":                 {input: "a:b", want: "a:b"},
+		"bare_literal":         {input: "b", want: "b"},
+		"wildcard":             {input: "a:b*", want: "a:b*"},
+		"regexp":               {input: "a:/b[0-9]+/", want: "a:/b[0-9]+/"},
+		"phrase":               {input: `a:"b c"`, want: `a:"b c"`},
+		"escaped_quote":        {input: `a:"b \"c\""`, want: `a:"b \"c\""`},
+		"quoted_number_string": {input: `a:"12"`, want: `a:"12"`},
+		"quoted_keyword":       {input: `a:"AND"`, want: `a:"AND"`},
+		"int":                  {input: "a:12", want: "a:12"},
+		"negative_float":       {input: "a:-1.5", want: "a:-1.5"},
+		"whole_float":          {input: "a:2.0", want: "a:2.0"},
+		"escaped_field":        {input: `a\:b:c`, want: `a\:b:c`},
+		"null":                 {input: "a:null", want: "a:null"},
+		"null_field":           {input: `\null:a`, want: `\null:a`},
+		"in_with_null":         {input: "a:(x OR null)", want: "a:(x OR null)"},
+		"comparisons":          {input: "a:>1 AND b:>=2 AND c:<3 AND d:<=4", want: "a:>1 AND b:>=2 AND c:<3 AND d:<=4"},
+		"inclusive_range":      {input: "a:[1 TO 5]", want: "a:[1 TO 5]"},
+		"exclusive_range":      {input: "a:{1 TO 5}", want: "a:{1 TO 5}"},
+		"open_range":           {input: "a:[1 TO *]", want: "a:[1 TO *]"},
+		"left_assoc":           {input: "a:b AND c:d AND e:f", want: "a:b AND c:d AND e:f"},
+		"right_nested":         {input: "a:b AND (c:d AND e:f)", want: "a:b AND (c:d AND e:f)"},
+		"precedence":           {input: "a:b OR c:d AND e:f", want: "a:b OR (c:d AND e:f)"},
+		"grouped_or":           {input: "(a:b OR c:d) AND e:f", want: "(a:b OR c:d) AND e:f"},
+		"implicit_and":         {input: "+a:b -c:d", want: "+a:b AND -c:d"},
+		"not":                  {input: "NOT a:b", want: "NOT a:b"},
+		"not_group":            {input: "NOT (a:b OR c:d)", want: "NOT (a:b OR c:d)"},
+		"boost":                {input: "a:b^2", want: "a:b^2"},
+		"fractional_boost":     {input: "a:b^0.5", want: "a:b^0.5"},
+		"default_boost":        {input: "a:b^", want: "a:b^1"},
+		"group_boost":          {input: "(a:b OR c:d)^2", want: "(a:b OR c:d)^2"},
+		"fuzzy":                {input: "a:b~", want: "a:b~1"},
+		"phrase_proximity":     {input: `a:"b c"~3`, want: `a:"b c"~3`},
+		"default_slop":         {input: `a:"b c"~`, want: `a:"b c"~0`},
+		"phrase_prefix":        {input: `a:"b c"*`, want: `a:"b c"*`},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			parsed, err := Parse(tc.input)
+			if err != nil {
+				t.Fatalf("unable to parse %q: %v", tc.input, err)
+			}
+			got, err := expr.ToLucene(parsed)
+			if err != nil {
+				t.Fatalf("unable to serialize %q: %v", tc.input, err)
+			}
+			if got != tc.want {
+				t.Fatalf("serialized lucene doesn't match:\n    wanted %s\n    got    %s", tc.want, got)
+			}
+			reparsed, err := Parse(got)
+			if err != nil {
+				t.Fatalf("unable to parse serialized %q: %v", got, err)
+			}
+			if !reflect.DeepEqual(parsed, reparsed) {
+				t.Fatalf("round trip doesn't match:\n    wanted %#v\n    got    %#v", parsed, reparsed)
+			}
+		})
+	}
+}
+
+func TestToLuceneExpressions(t *testing.T) {
+	type tc struct {
+		input *expr.Expression
+		want  string
+	}
+
+	tcs := map[string]tc{
+		"string_needing_quotes": {
+			input: expr.Eq("a", "b:c (d)"),
+			want:  `a:"b:c (d)"`,
+		},
+		"field_needing_escapes": {
+			input: expr.Eq("first name", "b"),
+			want:  `first\ name:b`,
+		},
+		"numeric_field": {
+			input: expr.Eq(expr.Lit(expr.Column("123")), "b"),
+			want:  `\123:b`,
+		},
+		"timestamp": {
+			input: expr.Rang("t", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), expr.WILD("*"), true),
+			want:  `t:["2024-01-01T00:00:00Z" TO *]`,
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			got, err := expr.ToLucene(tc.input)
+			if err != nil {
+				t.Fatalf("unable to serialize: %v", err)
+			}
+			if got != tc.want {
+				t.Fatalf("serialized lucene doesn't match:\n    wanted %s\n    got    %s", tc.want, got)
+			}
+			if _, err := Parse(got); err != nil {
+				t.Fatalf("unable to parse serialized %q: %v", got, err)
+			}
+		})
+	}
+
+	if _, err := expr.ToLucene(expr.Eq(expr.Lit(expr.RawColumn("a->>'b'")), "c")); err == nil {
+		t.Fatalf("expected an error serializing a raw column")
+	}
+}
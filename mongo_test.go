@@ -0,0 +1,140 @@
+package lucene
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestMongoEndToEnd(t *testing.T) {
+	type tc struct {
+		input        string
+		want         string
+		defaultField string
+		err          string
+	}
+
+	tcs := map[string]tc{
+		"basic_equal": {
+			input: "a:b",
+			want:  `{"a":"b"}`,
+		},
+		"basic_equal_with_number": {
+			input: "a:5",
+			want:  `{"a":5}`,
+		},
+		"phrase": {
+			input: `a:"foo bar"`,
+			want:  `{"a":"foo bar"}`,
+		},
+		"bare_term_with_default_field": {
+			input:        `foo`,
+			defaultField: "a",
+			want:         `{"a":"foo"}`,
+		},
+		"bare_term_errors": {
+			input: `foo`,
+			err:   "requires a field",
+		},
+		"and_flattens": {
+			input: "a:1 AND b:2 AND c:3",
+			want:  `{"$and":[{"a":1},{"b":2},{"c":3}]}`,
+		},
+		"or": {
+			input: "a:1 OR b:2",
+			want:  `{"$or":[{"a":1},{"b":2}]}`,
+		},
+		"must_and_must_not": {
+			input: "+a:1 -b:2",
+			want:  `{"$and":[{"a":1},{"$nor":[{"b":2}]}]}`,
+		},
+		"not_group": {
+			input: "NOT (a:1 OR b:2)",
+			want:  `{"$nor":[{"$or":[{"a":1},{"b":2}]}]}`,
+		},
+		"wildcard": {
+			input: "a:fo?b*",
+			want:  `{"a":{"$options":"s","$regex":"^fo.b.*$"}}`,
+		},
+		"not_wildcard": {
+			input: "NOT a:foo*",
+			want:  `{"a":{"$not":{"$options":"s","$regex":"^foo.*$"}}}`,
+		},
+		"regexp": {
+			input: "a:/fo+/",
+			want:  `{"a":{"$regex":"fo+"}}`,
+		},
+		"exists": {
+			input: "a:*",
+			want:  `{"a":{"$exists":true}}`,
+		},
+		"unbounded_range_exists": {
+			input: "a:[* TO *]",
+			want:  `{"a":{"$exists":true}}`,
+		},
+		"null": {
+			input: "a:null",
+			want:  `{"a":null}`,
+		},
+		"not_null": {
+			input: "NOT a:null",
+			want:  `{"$nor":[{"a":null}]}`,
+		},
+		"inclusive_range": {
+			input: "a:[1 TO 5]",
+			want:  `{"a":{"$gte":1,"$lte":5}}`,
+		},
+		"exclusive_range": {
+			input: "a:{1 TO 5}",
+			want:  `{"a":{"$gt":1,"$lt":5}}`,
+		},
+		"half_open_range": {
+			input: "a:[1 TO *]",
+			want:  `{"a":{"$gte":1}}`,
+		},
+		"comparison": {
+			input: "a:>=10",
+			want:  `{"a":{"$gte":10}}`,
+		},
+		"in": {
+			input: "a:(x OR y)",
+			want:  `{"a":{"$in":["x","y"]}}`,
+		},
+		"in_with_null": {
+			input: "a:(x OR null)",
+			want:  `{"a":{"$in":["x",null]}}`,
+		},
+		"comparison_with_null_errors": {
+			input: "a:>null",
+			err:   "comparison operator GREATER cannot be used with null",
+		},
+		"fuzzy_errors": {
+			input: "a:foo~",
+			err:   "unable to render operator [FUZZY]",
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			filter, err := ToMongo(tc.input, WithDefaultField(tc.defaultField))
+			if err != nil {
+				if tc.err != "" && strings.Contains(err.Error(), tc.err) {
+					return
+				}
+				t.Fatalf("unexpected error rendering expression: %v", err)
+			}
+
+			if tc.err != "" {
+				t.Fatalf("\nexpected error [%s]\ngot: %v", tc.err, filter)
+			}
+
+			got, err := json.Marshal(filter)
+			if err != nil {
+				t.Fatalf("unable to marshal filter: %v", err)
+			}
+			if string(got) != tc.want {
+				t.Fatalf("\nwant %s\ngot  %s\n", tc.want, got)
+			}
+		})
+	}
+}
@@ -68,11 +68,11 @@ func TestMySQLSQLEndToEnd(t *testing.T) {
 		},
 		"basic_fuzzy": {
 			input: "b AND a~",
-			err:   "unable to render operator [FUZZY]",
+			err:   "fuzzy term a requires a field",
 		},
 		"fuzzy_power": {
 			input: "b AND a~10",
-			err:   "unable to render operator [FUZZY]",
+			err:   "fuzzy term a requires a field",
 		},
 		"basic_boost": {
 			input: "b AND a^",
@@ -223,8 +223,9 @@ func TestMySQLSQLEndToEnd(t *testing.T) {
 			want:  "(((`title` = 'foo') OR (`title` = 'bar')) AND ((`body` = 'foo') OR (`body` = 'bar'))) OR (`k` = 'v')",
 		},
 		"fuzzy_key_value": {
-			input: "a:b~2 AND foo",
-			err:   "unable to render operator [FUZZY]",
+			input:        "a:b~2 AND foo",
+			want:         "(SOUNDEX(`a`) = SOUNDEX('b')) AND (`c` = 'foo')",
+			defaultField: "c",
 		},
 		"precedence_works": {
 			input: "a:b AND c:d OR e:f OR h:i AND j:k",
@@ -512,11 +513,11 @@ func TestMySQLParameterizedSQLEndToEnd(t *testing.T) {
 		},
 		"basic_fuzzy": {
 			input: "b AND a~",
-			err:   "unable to render operator [FUZZY]",
+			err:   "fuzzy term a requires a field",
 		},
 		"fuzzy_power": {
 			input: "b AND a~10",
-			err:   "unable to render operator [FUZZY]",
+			err:   "fuzzy term a requires a field",
 		},
 		"basic_boost": {
 			input: "b AND a^",
@@ -701,8 +702,10 @@ func TestMySQLParameterizedSQLEndToEnd(t *testing.T) {
 			wantParams: []any{"foo", "bar", "foo", "bar", "v"},
 		},
 		"fuzzy_key_value": {
-			input: "a:b~2 AND foo",
-			err:   "unable to render operator [FUZZY]",
+			input:        "a:b~2 AND foo",
+			wantStr:      "(SOUNDEX(`a`) = SOUNDEX(?)) AND (`c` = ?)",
+			wantParams:   []any{"b", "foo"},
+			defaultField: "c",
 		},
 		"precedence_works": {
 			input:      "a:b AND c:d OR e:f OR h:i AND j:k",
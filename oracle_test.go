@@ -0,0 +1,143 @@
+package lucene
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestOracleSQLEndToEnd(t *testing.T) {
+	type tc struct {
+		input        string
+		want         string
+		defaultField string
+		err          string
+	}
+
+	tcs := map[string]tc{
+		"basic_equal": {
+			input: "a:b",
+			want:  `"A" = 'b'`,
+		},
+		"basic_equal_with_number": {
+			input: "a:5",
+			want:  `"A" = 5`,
+		},
+		"escaped_field_keeps_case": {
+			input: `first\ name:bob`,
+			want:  `"first name" = 'bob'`,
+		},
+		"basic_wild_equal_with_*": {
+			input: "a:b*",
+			want:  `"A" LIKE 'b%' ESCAPE '\'`,
+		},
+		"wild_with_like_metacharacters": {
+			input: "a:100%_*",
+			want:  `"A" LIKE '100\%\_%' ESCAPE '\'`,
+		},
+		"standalone_wild": {
+			input: "a:*",
+			want:  `"A" IS NOT NULL`,
+		},
+		"regexp": {
+			input: "a:/b.*/",
+			want:  `REGEXP_LIKE("A", 'b.*')`,
+		},
+		"basic_inclusive_range": {
+			input: "a:[1 TO 5]",
+			want:  `"A" >= 1 AND "A" <= 5`,
+		},
+		"string_range": {
+			input: "a:{aaa TO bbb}",
+			want:  `"A" > 'aaa' AND "A" < 'bbb'`,
+		},
+		"in_list": {
+			input: "a:(b OR c)",
+			want:  `"A" IN ('b', 'c')`,
+		},
+		"null": {
+			input: "a:null",
+			want:  `"A" IS NULL`,
+		},
+		"must_not": {
+			input: "a:b -c:d",
+			want:  `("A" = 'b') AND (NOT("C" = 'd'))`,
+		},
+		"default_field": {
+			input:        "foo",
+			defaultField: "a",
+			want:         `"A" = 'foo'`,
+		},
+		"fuzzy": {
+			input: "a:foo~2",
+			want:  `UTL_MATCH.EDIT_DISTANCE("A", 'foo') <= 2`,
+		},
+		"column_with_quote_errors": {
+			input: `a\"b:c`,
+			err:   "column name contains a double quote",
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			got, err := ToOracle(tc.input, WithDefaultField(tc.defaultField))
+			if err != nil {
+				if tc.err != "" && strings.Contains(err.Error(), tc.err) {
+					return
+				}
+				t.Fatalf("unexpected error rendering expression: %v", err)
+			}
+
+			if tc.err != "" {
+				t.Fatalf("\nexpected error [%s]\ngot: %s", tc.err, got)
+			}
+
+			if got != tc.want {
+				t.Fatalf("\nwant %s\ngot  %s\n", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestOracleParameterizedSQLEndToEnd(t *testing.T) {
+	type tc struct {
+		input      string
+		wantStr    string
+		wantParams []any
+	}
+
+	tcs := map[string]tc{
+		"basic_equal": {
+			input:      "a:b",
+			wantStr:    `"A" = :1`,
+			wantParams: []any{"b"},
+		},
+		"numbered_in_order": {
+			input:      "a:b AND c:[1 TO 5] AND d:/x+/",
+			wantStr:    `(("A" = :1) AND ("C" >= :2 AND "C" <= :3)) AND (REGEXP_LIKE("D", :4))`,
+			wantParams: []any{"b", 1, 5, "x+"},
+		},
+		"wildcard": {
+			input:      "a:b_*",
+			wantStr:    `"A" LIKE :1 ESCAPE '\'`,
+			wantParams: []any{`b\_%`},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			gotStr, gotParams, err := ToParameterizedOracle(tc.input)
+			if err != nil {
+				t.Fatalf("unexpected error rendering expression: %v", err)
+			}
+
+			if gotStr != tc.wantStr {
+				t.Fatalf("\nwant %s\ngot  %s\n", tc.wantStr, gotStr)
+			}
+
+			if !reflect.DeepEqual(gotParams, tc.wantParams) {
+				t.Fatalf("expected params %v, got %v", tc.wantParams, gotParams)
+			}
+		})
+	}
+}
@@ -1,10 +1,9 @@
 package lucene
 
 import (
-	"fmt"
-	"reflect"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/grindlemire/go-lucene/internal/lex"
 	"github.com/grindlemire/go-lucene/pkg/lucene/expr"
@@ -22,9 +21,11 @@ func WithDefaultField(field string) Opt {
 }
 
 // Parse will parse a lucene expression string using a buffer and the shift reduce algorithm. The returned expression
-// is an AST that can be rendered to a variety of different formats.
+// is an AST that can be rendered to a variety of different formats. Syntax errors are returned as a *ParseError
+// that points at the offending token in the input.
 func Parse(input string, opts ...Opt) (e *expr.Expression, err error) {
 	p := &parser{
+		input:        input,
 		lex:          lex.Lex(input),
 		stack:        []any{},
 		nonTerminals: []lex.Token{{Typ: lex.TStart}},
@@ -39,46 +40,82 @@ func Parse(input string, opts ...Opt) (e *expr.Expression, err error) {
 		return e, err
 	}
 
-	err = expr.Validate(ex)
+	err = p.validate(ex)
 	if err != nil {
 		return e, err
 	}
 
+	err = p.applySchema(ex)
+	if err != nil {
+		return e, err
+	}
+
+	if p.restrictsFields() {
+		err = p.resolveFields(ex)
+		if err != nil {
+			return e, err
+		}
+	}
+
 	return ex, nil
 }
 
 type parser struct {
+	input        string
 	lex          *lex.Lexer
 	stack        []any
 	nonTerminals []lex.Token
 
 	defaultField string
+
+	allowedFields map[string]struct{}
+	fieldMapping  map[string]string
+
+	schema Schema
+	// literalText remembers the original text of numeric literals so a schema can
+	// reinterpret them, e.g. as the string "02134" instead of the int 2134.
+	literalText map[*expr.Expression]string
+
+	// now is the clock used to resolve date math
+	now func() time.Time
+
+	// spans remembers the range of the input each expression was parsed from so errors
+	// found after parsing can point at it.
+	spans map[*expr.Expression]span
+
+	// quoted remembers the literals lexed from quoted phrases, which ~ turns into proximity
+	// matches rather than fuzzy terms.
+	quoted map[*expr.Expression]bool
 }
 
 func (p *parser) parse() (e *expr.Expression, err error) {
 	for {
 		next := p.lex.Peek()
+		if next.Typ == lex.TErr {
+			return e, p.lexError(next)
+		}
+
 		if p.shouldAccept(next) {
 			if len(p.stack) != 1 {
-				return e, fmt.Errorf("multiple expressions left after parsing: %v", p.stack)
+				return e, p.syntaxError(p.stack)
 			}
 			final, ok := p.stack[0].(*expr.Expression)
 			if !ok {
-				return e, fmt.Errorf(
-					"final parse didn't return an expression: %s [type: %s]",
-					p.stack[0],
-					reflect.TypeOf(final),
-				)
+				return e, p.syntaxError(p.stack)
 			}
 
 			// edge case for a single literal in the expression and a default field specified
-			if final.Op == expr.Literal && p.defaultField != "" {
-				final = expr.Expr(p.defaultField, expr.Equals, final.Left)
+			sp, hasSpan := p.spans[final]
+			if (final.Op == expr.Literal || final.Op == expr.Wild || final.Op == expr.Regexp) && p.defaultField != "" {
+				final = expr.Eq(expr.Column(p.defaultField), final)
 			}
 
 			if final.Op == expr.Null && p.defaultField != "" {
 				final = expr.Eq(p.defaultField, final)
 			}
+			if hasSpan {
+				p.trackSpan(final, sp)
+			}
 
 			return final, nil
 		}
@@ -89,7 +126,12 @@ func (p *parser) parse() (e *expr.Expression, err error) {
 				// if we have a terminal parse it and put it on the stack
 				lit, err := parseLiteral(tok)
 				if err != nil {
-					return e, err
+					return e, newParseError(p.input, tok.Pos(), tok.Val, err.Error(), "")
+				}
+				p.rememberLiteralText(lit, tok)
+				p.rememberQuoted(lit, tok)
+				if litExpr, ok := lit.(*expr.Expression); ok {
+					p.trackSpan(litExpr, span{start: tok.Pos(), end: tok.Pos() + len(tok.Val)})
 				}
 
 				// we should always check if the current top of the stack is another token
@@ -227,7 +269,7 @@ func (p *parser) reduce() (err error) {
 	top := []any{}
 	for {
 		if len(p.stack) == 0 {
-			return fmt.Errorf("error parsing, no items left to reduce, current state: %v", top)
+			return p.syntaxError(top)
 		}
 
 		// pull the top off the stack
@@ -239,10 +281,21 @@ func (p *parser) reduce() (err error) {
 
 		// try to reduce with all our reducers
 		var reduced bool
-		top, p.nonTerminals, reduced = reduce.Reduce(top, p.nonTerminals, p.defaultField)
+		consumed, hasSpan := p.spanOf(top)
+		top, p.nonTerminals, reduced = reduce.Reduce(top, p.nonTerminals, p.defaultField, p.isQuoted)
 
 		// if we consumed some non terminals during the reduce it means we successfully reduced
 		if reduced {
+			// the new expressions were parsed from the part of the input they consumed, which
+			// for a group includes its parentheses
+			for _, s := range top {
+				if e, ok := s.(*expr.Expression); ok && hasSpan {
+					if _, known := p.spans[e]; !known || len(top) == 1 {
+						p.trackSpan(e, consumed)
+					}
+				}
+			}
+
 			// If the reducer returned multiple elements and the first two are both expressions,
 			// we need to inject an implicit AND between them (this happens when fuzzy/boost
 			// does a partial reduction like [FUZZY(...), other-expr])
@@ -266,9 +319,49 @@ func (p *parser) reduce() (err error) {
 	}
 }
 
+// rememberLiteralText records the source text of a numeric literal when a schema is set.
+func (p *parser) rememberLiteralText(lit any, tok lex.Token) {
+	if p.schema == nil {
+		return
+	}
+	e, ok := lit.(*expr.Expression)
+	if !ok || e.Op != expr.Literal {
+		return
+	}
+	switch e.Left.(type) {
+	case int, float64:
+		if p.literalText == nil {
+			p.literalText = map[*expr.Expression]string{}
+		}
+		p.literalText[e] = tok.Val
+	}
+}
+
+// rememberQuoted records a literal lexed from a quoted phrase.
+func (p *parser) rememberQuoted(lit any, tok lex.Token) {
+	e, ok := lit.(*expr.Expression)
+	if !ok || tok.Typ != lex.TQuoted {
+		return
+	}
+	if p.quoted == nil {
+		p.quoted = map[*expr.Expression]bool{}
+	}
+	p.quoted[e] = true
+}
+
+// isQuoted reports whether the literal was lexed from a quoted phrase.
+func (p *parser) isQuoted(e *expr.Expression) bool {
+	return p.quoted[e]
+}
+
 func parseLiteral(token lex.Token) (e any, err error) {
 	// strip the delimiters (either " or ') and unescape \<delim> and \\.
 	if token.Typ == lex.TQuoted {
+		// a phrase prefix ("quick fo"*) is a wildcard on the phrase, whose own * and ? match
+		// themselves
+		if phrase, found := strings.CutSuffix(token.Val, "*"); found {
+			return expr.WILD(escapeWildcard(unescapePhrase(phrase)) + "*"), nil
+		}
 		return expr.Lit(unescapePhrase(token.Val)), nil
 	}
 
@@ -309,6 +402,12 @@ func parseLiteral(token lex.Token) (e any, err error) {
 	return expr.Lit(token.Val), nil
 }
 
+// escapeWildcard escapes the wildcard characters of a phrase with a backslash so it can be used as
+// the literal part of a wildcard.
+func escapeWildcard(phrase string) string {
+	return strings.NewReplacer(`\`, `\\`, `*`, `\*`, `?`, `\?`).Replace(phrase)
+}
+
 // unescapePhrase strips the surrounding quote delimiters off a TQuoted token's
 // raw value and unescapes only the delimiter itself and a literal backslash,
 // e.g. \" -> " and \\ -> \. Any other backslash sequence is left untouched so
@@ -2,6 +2,7 @@ package lucene
 
 import (
 	"encoding/json"
+	"errors"
 	"reflect"
 	"testing"
 
@@ -137,6 +138,10 @@ func TestParseLucene(t *testing.T) {
 			input: `url:/example.com\/foo\/bar\/.*/`,
 			want:  expr.Eq("url", expr.REGEXP(`/example.com\/foo\/bar\/.*/`)),
 		},
+		"regexp_with_flag": {
+			input: "a:/b+/i AND c",
+			want:  expr.AND(expr.Eq("a", expr.REGEXP("/b+/i")), "c"),
+		},
 		"basic_default_AND": {
 			input: "a b",
 			want:  expr.AND("a", "b"),
@@ -448,13 +453,43 @@ func TestParseLucene(t *testing.T) {
 				expr.Eq("a", "b"),
 			),
 		},
-		"fuzzy_quoted_literal": {
+		"proximity_quoted_literal": {
 			input: `"foo bar"~4 AND a:b`,
 			want: expr.AND(
-				expr.FUZZY(expr.Lit("foo bar"), 4),
+				expr.PROXIMITY(expr.Lit("foo bar"), 4),
 				expr.Eq("a", "b"),
 			),
 		},
+		"proximity_phrase": {
+			input: `a:"quick fox"~5`,
+			want:  expr.PROXIMITY(expr.Eq("a", "quick fox"), 5),
+		},
+		"proximity_without_slop": {
+			input: `a:"quick fox"~ OR b:c`,
+			want: expr.OR(
+				expr.PROXIMITY(expr.Eq("a", "quick fox"), 0),
+				expr.Eq("b", "c"),
+			),
+		},
+		"proximity_quoted_word": {
+			input: `a:"foo"~2`,
+			want:  expr.PROXIMITY(expr.Eq("a", "foo"), 2),
+		},
+		"fuzzy_word_with_escaped_space": {
+			input: `a:foo\ bar~2`,
+			want:  expr.FUZZY(expr.Eq("a", "foo bar"), 2),
+		},
+		"phrase_prefix_with_wildcard_characters": {
+			input: `a:"what? *"*`,
+			want:  expr.LIKE("a", expr.WILD(`what\? \**`)),
+		},
+		"phrase_prefix": {
+			input: `a:"quick fo"* AND b:c`,
+			want: expr.AND(
+				expr.LIKE("a", "quick fo*"),
+				expr.Eq("b", "c"),
+			),
+		},
 		"fuzzy_sub_expression": {
 			input: "(title:foo OR title:bar)~2 AND (body:foo OR body:bar)",
 			want: expr.AND(
@@ -825,6 +860,30 @@ func TestParseLuceneWithDefaultField(t *testing.T) {
 			defaultField: "foo",
 			want:         expr.Eq("foo", expr.Lit("")),
 		},
+		"single_wildcard": {
+			input:        "a*",
+			defaultField: "foo",
+			want:         expr.LIKE("foo", "a*"),
+		},
+		"single_regexp": {
+			input:        "/b+/",
+			defaultField: "foo",
+			want:         expr.LIKE("foo", expr.REGEXP("/b+/")),
+		},
+		"wildcard_without_default_field": {
+			input: "a* OR /b+/",
+			want:  expr.OR(expr.WILD("a*"), expr.REGEXP("/b+/")),
+		},
+		"wildcard_and_regexp": {
+			input:        "a* -/b+/",
+			defaultField: "foo",
+			want:         expr.AND(expr.LIKE("foo", "a*"), expr.MUSTNOT(expr.LIKE("foo", expr.REGEXP("/b+/")))),
+		},
+		"proximity_and_fuzzy": {
+			input:        `"quick fox"~2 AND fox~`,
+			defaultField: "foo",
+			want:         expr.AND(expr.PROXIMITY(expr.Eq("foo", "quick fox"), 2), expr.FUZZY(expr.Eq("foo", "fox"), 1)),
+		},
 	}
 
 	for name, tc := range tcs {
@@ -948,6 +1007,93 @@ func TestParseFailure(t *testing.T) {
 	}
 }
 
+func TestParseErrorPosition(t *testing.T) {
+	type tc struct {
+		input string
+		want  ParseError
+	}
+
+	tcs := map[string]tc{
+		"unclosed_paren": {
+			input: "a:b AND (c OR d",
+			want:  ParseError{Offset: 8, Line: 1, Column: 9, Token: "(", Expected: `")"`},
+		},
+		"unexpected_close_paren": {
+			input: "a:b)",
+			want:  ParseError{Offset: 3, Line: 1, Column: 4, Token: ")"},
+		},
+		"and_without_rhs": {
+			input: "a:b AND",
+			want:  ParseError{Offset: 4, Line: 1, Column: 5, Token: "AND", Expected: "expression"},
+		},
+		"and_without_lhs": {
+			input: "AND a",
+			want:  ParseError{Offset: 0, Line: 1, Column: 1, Token: "AND", Expected: "expression"},
+		},
+		"not_without_subexpression": {
+			input: "a:b NOT",
+			want:  ParseError{Offset: 4, Line: 1, Column: 5, Token: "NOT", Expected: "expression"},
+		},
+		"empty_group": {
+			input: "a:()",
+			want:  ParseError{Offset: 3, Line: 1, Column: 4, Token: ")", Expected: "expression"},
+		},
+		"range_without_max": {
+			input: "a:[1 TO ]",
+			want:  ParseError{Offset: 5, Line: 1, Column: 6, Token: "TO", Expected: "expression"},
+		},
+		"unclosed_range": {
+			input: "a:{1 TO 5",
+			want:  ParseError{Offset: 2, Line: 1, Column: 3, Token: "{", Expected: `"}"`},
+		},
+		"range_with_two_tos": {
+			input: "a:[1 TO 2 TO 3]",
+			want:  ParseError{Offset: 10, Line: 1, Column: 11, Token: "TO", Expected: `"]"`},
+		},
+		"field_of_a_field": {
+			input: "x AND a:b:c",
+			want:  ParseError{Offset: 6, Line: 1, Column: 7, Token: "a:b:c"},
+		},
+		"field_of_a_group": {
+			input: "x AND (a:b):c",
+			want:  ParseError{Offset: 6, Line: 1, Column: 7, Token: "(a:b):c"},
+		},
+		"unterminated_quote": {
+			input: `a:b AND c:"foo bar`,
+			want:  ParseError{Offset: 10, Line: 1, Column: 11, Token: `"foo bar`, Expected: `closing '"'`},
+		},
+		"unterminated_regexp": {
+			input: `a:/foo`,
+			want:  ParseError{Offset: 2, Line: 1, Column: 3, Token: `/foo`, Expected: `closing '/'`},
+		},
+		"multiline": {
+			input: "a:b\nAND (c",
+			want:  ParseError{Offset: 8, Line: 2, Column: 5, Token: "(", Expected: `")"`},
+		},
+		"column_counts_runes": {
+			input: "ä:ö AND",
+			want:  ParseError{Offset: 6, Line: 1, Column: 5, Token: "AND", Expected: "expression"},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			_, err := Parse(tc.input)
+			var perr *ParseError
+			if !errors.As(err, &perr) {
+				t.Fatalf("expected a *ParseError, got: %v", err)
+			}
+
+			// the message is free form so only compare the structured fields
+			got := *perr
+			got.Msg = ""
+			if got != tc.want {
+				t.Fatalf(errTemplate, "parse error doesn't match", tc.want, got)
+			}
+		})
+	}
+}
+
 func FuzzParse(f *testing.F) {
 	tcs := []string{
 		"A:B AND C:D",
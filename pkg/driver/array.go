@@ -0,0 +1,133 @@
+package driver
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/grindlemire/go-lucene/pkg/lucene/expr"
+)
+
+// isArrayField reports whether the column on the left of an operator holds an array of values.
+func (b Base) isArrayField(column any) bool {
+	name := fieldName(column)
+	return name != "" && slices.Contains(b.ArrayFields, name)
+}
+
+// arrayMatch reports whether the expression matches the elements of an array field rather than
+// its value: a term, list, wildcard, comparison or range on one, or the negation of field:null.
+func (b Base) arrayMatch(e *expr.Expression) bool {
+	switch e.Op {
+	case expr.Equals, expr.In, expr.Like, expr.Range, expr.Greater, expr.GreaterEq, expr.Less, expr.LessEq:
+		return b.isArrayField(e.Left)
+	case expr.Not, expr.MustNot:
+		inner, _ := e.Left.(*expr.Expression)
+		return inner != nil && isNullEquals(inner) && b.isArrayField(inner.Left)
+	}
+	return false
+}
+
+func (b Base) renderArray(e *expr.Expression) (string, error) {
+	s, _, err := b.renderArrayMatch(e, false)
+	return s, err
+}
+
+func (b Base) renderArrayParam(e *expr.Expression) (string, []any, error) {
+	return b.renderArrayMatch(e, true)
+}
+
+// renderArrayMatch renders an expression on an array field. A field is null when it has no
+// elements, a term or list matches when any element is one of its values and anything else,
+// e.g. tags:urg* or scores:>5, matches when any element does.
+func (b Base) renderArrayMatch(e *expr.Expression, param bool) (string, []any, error) {
+	column := e.Left
+	if e.Op == expr.Not || e.Op == expr.MustNot {
+		column = e.Left.(*expr.Expression).Left
+	}
+	d, ok := b.dialect().(ArrayDialect)
+	if !ok {
+		return "", nil, fmt.Errorf("unable to render array field %s: the dialect has no array support", fieldName(column))
+	}
+	left, params, err := b.serializeAny(column, param)
+	if err != nil {
+		return "", nil, err
+	}
+	length, err := withQuestionMarks(func(args []string) (string, error) { return d.RenderArrayLength(args[0]) }, left)
+	if err != nil {
+		return "", nil, err
+	}
+	empty := fmt.Sprintf("(%s IS NULL OR %s = 0)", left, length)
+
+	right, _ := e.Right.(*expr.Expression)
+	switch {
+	case e.Op == expr.Not || e.Op == expr.MustNot:
+		return fmt.Sprintf("%s > 0", length), params, nil
+	case e.Op == expr.Equals && isNullExpr(right):
+		return empty, params, nil
+	case e.Op == expr.Like && right != nil && right.Op == expr.Wild && right.Left == "*":
+		return fmt.Sprintf("%s > 0", length), params, nil
+	case e.Op == expr.Equals && !b.ignoresCase(e):
+		return b.renderArrayContains(d, left, params, []*expr.Expression{right}, 0, empty, param)
+	case e.Op == expr.In:
+		values, nullCount, ok := partitionNullsFromList(e.Right)
+		if ok {
+			return b.renderArrayContains(d, left, params, values, nullCount, empty, param)
+		}
+	}
+
+	// match each element with the same operator, through a base that doesn't treat the element
+	// as an array field and folds case if the field does
+	source, element := d.ArrayElements(left)
+	eb := b
+	eb.ArrayFields = nil
+	eb.CaseInsensitive = b.CaseInsensitive || slices.Contains(b.CaseInsensitiveFields, fieldName(e.Left))
+	match := *e
+	match.Left = expr.Lit(expr.RawColumn(element))
+	cond, cparams, err := eb.renderAny(&match, param)
+	if err != nil {
+		return "", nil, err
+	}
+	return fmt.Sprintf("EXISTS (SELECT 1 FROM %s WHERE %s)", source, cond), append(params, cparams...), nil
+}
+
+// renderArrayContains renders a match of an array holding any of the values, or no values at
+// all when the list also had nulls.
+func (b Base) renderArrayContains(d ArrayDialect, left string, params []any, values []*expr.Expression, nullCount int, empty string, param bool) (string, []any, error) {
+	if len(values) == 0 {
+		return empty, params, nil
+	}
+	strs := []string{}
+	for _, v := range values {
+		s, vparams, err := b.serializeAny(v, param)
+		if err != nil {
+			return "", nil, err
+		}
+		strs = append(strs, s)
+		params = append(params, vparams...)
+	}
+	s, err := withQuestionMarks(func(args []string) (string, error) { return d.RenderArrayContains(args[0], args[1:]) }, append([]string{left}, strs...)...)
+	if err != nil {
+		return "", nil, err
+	}
+	if nullCount > 0 {
+		s = fmt.Sprintf("(%s OR %s)", s, empty)
+	}
+	return s, params, nil
+}
+
+// serializeAny is serialize or serializeParams, depending on param.
+func (b Base) serializeAny(in any, param bool) (string, []any, error) {
+	if param {
+		return b.serializeParams(in)
+	}
+	s, err := b.serialize(in)
+	return s, nil, err
+}
+
+// renderAny is Render or RenderParam, depending on param.
+func (b Base) renderAny(e *expr.Expression, param bool) (string, []any, error) {
+	if param {
+		return b.renderParam(e)
+	}
+	s, err := b.render(e)
+	return s, nil, err
+}
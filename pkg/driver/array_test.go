@@ -0,0 +1,177 @@
+package driver
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/grindlemire/go-lucene/pkg/lucene/expr"
+)
+
+func TestArrayFields(t *testing.T) {
+	postgres := NewPostgresDriver()
+	postgres.ArrayFields = []string{"tags", "scores"}
+	mysql := NewMySQLDriver()
+	mysql.ArrayFields = []string{"tags", "scores"}
+	sqlite := NewSQLiteDriver()
+	sqlite.ArrayFields = []string{"tags", "scores"}
+	oracle := NewOracleDriver()
+	oracle.ArrayFields = []string{"tags"}
+	folded := NewPostgresDriver()
+	folded.ArrayFields = []string{"tags"}
+	folded.CaseInsensitiveFields = []string{"tags"}
+
+	type tc struct {
+		driver interface {
+			Render(*expr.Expression) (string, error)
+		}
+		input *expr.Expression
+		want  string
+		err   string
+	}
+
+	tcs := map[string]tc{
+		"postgres_equals": {
+			driver: postgres,
+			input:  expr.Eq("tags", "urgent"),
+			want:   `'urgent' = ANY("tags")`,
+		},
+		"postgres_list": {
+			driver: postgres,
+			input:  expr.IN("tags", expr.LIST(expr.Lit("a"), expr.Lit("b"))),
+			want:   `"tags" && ARRAY['a', 'b']`,
+		},
+		"postgres_list_with_null": {
+			driver: postgres,
+			input:  expr.IN("tags", expr.LIST(expr.Lit("a"), expr.NULL())),
+			want:   `('a' = ANY("tags") OR ("tags" IS NULL OR cardinality("tags") = 0))`,
+		},
+		"postgres_wildcard": {
+			driver: postgres,
+			input:  expr.LIKE("tags", "ur*"),
+			want:   `EXISTS (SELECT 1 FROM unnest("tags") AS elem WHERE elem SIMILAR TO 'ur%')`,
+		},
+		"postgres_range": {
+			driver: postgres,
+			input:  expr.Rang("scores", 1, 5, true),
+			want:   `EXISTS (SELECT 1 FROM unnest("scores") AS elem WHERE elem >= 1 AND elem <= 5)`,
+		},
+		"postgres_null": {
+			driver: postgres,
+			input:  expr.Eq("tags", expr.NULL()),
+			want:   `("tags" IS NULL OR cardinality("tags") = 0)`,
+		},
+		"postgres_not_null": {
+			driver: postgres,
+			input:  expr.NOT(expr.Eq("tags", expr.NULL())),
+			want:   `cardinality("tags") > 0`,
+		},
+		"postgres_any_value": {
+			driver: postgres,
+			input:  expr.LIKE("tags", "*"),
+			want:   `cardinality("tags") > 0`,
+		},
+		"postgres_negated_term": {
+			driver: postgres,
+			input:  expr.AND(expr.Eq("status", "open"), expr.NOT(expr.Eq("tags", "spam"))),
+			want:   `("status" = 'open') AND (NOT('spam' = ANY("tags")))`,
+		},
+		"postgres_case_insensitive": {
+			driver: folded,
+			input:  expr.Eq("tags", "Urgent"),
+			want:   `EXISTS (SELECT 1 FROM unnest("tags") AS elem WHERE LOWER(elem) = LOWER('Urgent'))`,
+		},
+		"mysql_equals": {
+			driver: mysql,
+			input:  expr.Eq("tags", "urgent"),
+			want:   "'urgent' MEMBER OF(`tags`)",
+		},
+		"mysql_list": {
+			driver: mysql,
+			input:  expr.IN("tags", expr.LIST(expr.Lit("a"), expr.Lit("b"))),
+			want:   "JSON_OVERLAPS(`tags`, JSON_ARRAY('a', 'b'))",
+		},
+		"mysql_wildcard": {
+			driver: mysql,
+			input:  expr.LIKE("tags", "ur*"),
+			want:   "EXISTS (SELECT 1 FROM JSON_TABLE(`tags`, '$[*]' COLUMNS (elem TEXT PATH '$')) AS elems WHERE elem LIKE 'ur%' ESCAPE '#')",
+		},
+		"mysql_null": {
+			driver: mysql,
+			input:  expr.Eq("tags", expr.NULL()),
+			want:   "(`tags` IS NULL OR JSON_LENGTH(`tags`) = 0)",
+		},
+		"sqlite_equals": {
+			driver: sqlite,
+			input:  expr.Eq("tags", "urgent"),
+			want:   `EXISTS (SELECT 1 FROM json_each("tags") WHERE value = 'urgent')`,
+		},
+		"sqlite_list": {
+			driver: sqlite,
+			input:  expr.IN("tags", expr.LIST(expr.Lit("a"), expr.Lit("b"))),
+			want:   `EXISTS (SELECT 1 FROM json_each("tags") WHERE value IN ('a', 'b'))`,
+		},
+		"sqlite_wildcard": {
+			driver: sqlite,
+			input:  expr.LIKE("tags", "ur*"),
+			want:   `EXISTS (SELECT 1 FROM json_each("tags") WHERE value GLOB 'ur*')`,
+		},
+		"sqlite_not_null": {
+			driver: sqlite,
+			input:  expr.NOT(expr.Eq("tags", expr.NULL())),
+			want:   `json_array_length("tags") > 0`,
+		},
+		"not_without_an_expression_does_not_panic": {
+			driver: postgres,
+			input:  &expr.Expression{Op: expr.Not},
+			want:   "NOT()",
+		},
+		"unsupported_dialect": {
+			driver: oracle,
+			input:  expr.Eq("tags", "urgent"),
+			err:    "unable to render array field tags: the dialect has no array support",
+		},
+		"unsupported_dialect_not_null": {
+			driver: oracle,
+			input:  expr.NOT(expr.Eq("tags", expr.NULL())),
+			err:    "unable to render array field tags: the dialect has no array support",
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			got, err := tc.driver.Render(tc.input)
+			if tc.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Fatalf(errTemplate, "error doesn't match", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("got an unexpected error when rendering: %v", err)
+			}
+			if got != tc.want {
+				t.Fatalf(errTemplate, "generated sql doesn't match", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestArrayFieldsParam(t *testing.T) {
+	d := NewPostgresDriver()
+	d.ArrayFields = []string{"tags"}
+
+	got, params, err := d.RenderParam(expr.AND(
+		expr.Eq("status", "open"),
+		expr.AND(expr.IN("tags", expr.LIST(expr.Lit("a"), expr.Lit("b"))), expr.LIKE("tags", "ur*")),
+	))
+	if err != nil {
+		t.Fatalf("got an unexpected error when rendering: %v", err)
+	}
+	if want := `("status" = $1) AND (("tags" && ARRAY[$2, $3]) AND (EXISTS (SELECT 1 FROM unnest("tags") AS elem WHERE elem SIMILAR TO $4)))`; got != want {
+		t.Fatalf(errTemplate, "generated sql doesn't match", want, got)
+	}
+	if want := []any{"open", "a", "b", "ur%"}; !reflect.DeepEqual(params, want) {
+		t.Fatalf("params don't match:\n    wanted %v\n    got    %v", want, params)
+	}
+}
@@ -4,17 +4,32 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/grindlemire/go-lucene/pkg/lucene/expr"
 )
 
-// stripRegexpDelimiters removes surrounding /.../ delimiters from a Lucene
-// regexp literal, returning the inner pattern.
-func stripRegexpDelimiters(s string) string {
-	if len(s) >= 2 && s[0] == '/' && s[len(s)-1] == '/' {
-		return s[1 : len(s)-1]
+// likePattern returns the raw pattern of a wildcard or literal on the right of a Like.
+func likePattern(e *expr.Expression) (string, bool) {
+	if e == nil || (e.Op != expr.Wild && e.Op != expr.Literal) {
+		return "", false
 	}
-	return s
+	s, ok := e.Left.(string)
+	return s, ok
+}
+
+// stripRegexpDelimiters removes surrounding /.../ delimiters and any flags from
+// a Lucene regexp literal, returning the inner pattern.
+func stripRegexpDelimiters(s string) string {
+	pattern, _ := expr.SplitRegexp(s)
+	return pattern
+}
+
+// regexpIgnoresCase reports whether a Lucene regexp literal carries the i flag
+// (/pattern/i).
+func regexpIgnoresCase(s string) bool {
+	_, flags := expr.SplitRegexp(s)
+	return strings.Contains(flags, "i")
 }
 
 // isNullExpr returns true if the value is a *expr.Expression with Op == Null.
@@ -89,6 +104,39 @@ type Base struct {
 	// a Postgres-compatible default to preserve backwards compatibility for
 	// custom drivers built against the pre-dialect API.
 	Dialect Dialect
+	// Fuzzy renders fuzzy terms (field:term~N). If nil, Base uses the dialect's
+	// RenderFuzzy when it implements FuzzyDialect and fails to render fuzzy
+	// terms otherwise.
+	Fuzzy FuzzyFunc
+	// CaseInsensitive makes Equals, Like and Regexp ignore case on every field.
+	// Regexps written as /pattern/i ignore case regardless.
+	CaseInsensitive bool
+	// CaseInsensitiveFields makes Equals, Like and Regexp ignore case on just
+	// the listed fields.
+	CaseInsensitiveFields []string
+	// FullTextFields lists the text fields searched with the dialect's full-text
+	// search rather than compared for equality. Terms, phrases and prefixes on
+	// them, e.g. body:quick AND body:"brown fox" AND -body:lazy, become a single
+	// search in the engine's query syntax.
+	FullTextFields []string
+	// JSONColumns lists the columns that hold JSON documents. A dotted field on
+	// one of them, e.g. attrs.color for the attrs column, reads the value at that
+	// path inside the document instead of a column named "attrs.color". On
+	// Postgres the columns must be jsonb, since equalities render as jsonb
+	// containment (@>).
+	JSONColumns []string
+	// ArrayFields lists the fields that hold an array of values, such as a
+	// Postgres text[] or a JSON array. A term or list on one matches when any
+	// element is one of its values, a wildcard, comparison or range when any
+	// element matches it, and field:null when the array is null or empty.
+	ArrayFields []string
+	// Relations lists the one-to-many child tables queried through fields
+	// prefixed with their name. Clauses on them, e.g. comments.author:alice,
+	// become an EXISTS subquery over the child rows of each row.
+	Relations []Relation
+
+	// within is the relation whose subquery is being rendered.
+	within *Relation
 }
 
 // dialect returns the configured dialect, falling back to defaultDialect if
@@ -100,9 +148,16 @@ func (b Base) dialect() Dialect {
 	return b.Dialect
 }
 
-// RenderParam will render the expression into a parameterized query. The returned string will contain placeholders
-// and the params will contain the values that should be passed to the query.
+// RenderParam will render the expression into a parameterized query. The returned string will contain ? placeholders
+// and the params will contain the values that should be passed to the query. Boosts are rejected as in Render;
+// RenderScoredParam accepts them.
 func (b Base) RenderParam(e *expr.Expression) (s string, params []any, err error) {
+	return b.renderParamWith(e, questionMarks)
+}
+
+// renderParam renders the expression into a parameterized query with the placeholder marker in
+// place of each param, to be replaced by the driver's own placeholders.
+func (b Base) renderParam(e *expr.Expression) (s string, params []any, err error) {
 	if e == nil {
 		return "", params, nil
 	}
@@ -111,6 +166,30 @@ func (b Base) RenderParam(e *expr.Expression) (s string, params []any, err error
 		return "", nil, fmt.Errorf("null cannot be rendered as a standalone value")
 	}
 
+	if b.relationMatch(e) {
+		return b.renderRelationParam(e)
+	}
+
+	if e.Op == expr.Fuzzy {
+		return b.renderFuzzyParam(e)
+	}
+
+	if column, q, ok := b.fullTextQuery(e); ok {
+		return b.renderFullTextParam(column, q)
+	}
+
+	if e.Op == expr.Proximity {
+		inner, err := b.proximityPhrase(e)
+		if err != nil {
+			return "", nil, err
+		}
+		return b.renderParam(inner)
+	}
+
+	if b.arrayMatch(e) {
+		return b.renderArrayParam(e)
+	}
+
 	// Standalone Regexp expression: strip /.../ delimiters and return as a
 	// parameterized value. This mirrors what serializeParams does for nested
 	// Regexp sub-expressions.
@@ -120,7 +199,7 @@ func (b Base) RenderParam(e *expr.Expression) (s string, params []any, err error
 		if err := validateStringLiteral(s); err != nil {
 			return "", nil, err
 		}
-		return "?", []any{s}, nil
+		return placeholder, []any{s}, nil
 	}
 
 	// Not/MustNot wrapping Equals(field, Null) -> IS NOT NULL.
@@ -133,9 +212,13 @@ func (b Base) RenderParam(e *expr.Expression) (s string, params []any, err error
 		return fmt.Sprintf("%s IS NOT NULL", col), cparams, nil
 	}
 
+	if column, document, ok := b.jsonContains(e); ok {
+		return b.renderJSONContainsParam(column, document)
+	}
+
 	d := b.dialect()
 
-	left, lparams, err := b.serializeParams(e.Left)
+	left, lparams, err := b.serializeLeftParams(e)
 	if err != nil {
 		return s, params, err
 	}
@@ -199,8 +282,8 @@ func (b Base) RenderParam(e *expr.Expression) (s string, params []any, err error
 
 	// Standalone wildcard on a Like operator: `field:*`. Route through the
 	// dialect so each database can decide how to represent "any value".
-	if right == "'*'" && e.Op == expr.Like {
-		str, err := d.RenderStandaloneWild(left)
+	if right == d.EscapeStringLiteral("*") && e.Op == expr.Like {
+		str, err := withQuestionMarks(func(args []string) (string, error) { return d.RenderStandaloneWild(args[0]) }, left)
 		return str, lparams, err
 	}
 
@@ -210,12 +293,18 @@ func (b Base) RenderParam(e *expr.Expression) (s string, params []any, err error
 	// through the regex path (MySQL does this for patterns containing
 	// alternation, grouping, or character classes).
 	isRegex := false
+	fold := b.ignoresCase(e)
 	if e.Op == expr.Like {
 		if rightExpr, ok := e.Right.(*expr.Expression); ok && rightExpr.Op == expr.Regexp {
 			isRegex = true
+			if len(rparams) > 0 && fold {
+				if pattern, isStr := rparams[0].(string); isStr {
+					rparams[0] = b.prepareRegexpFold(pattern)
+				}
+			}
 		}
 		if !isRegex && len(rparams) > 0 {
-			transformed, useRegex := d.PrepareLikePattern(rparams[0].(string))
+			transformed, useRegex := b.prepareLikePattern(rparams[0].(string), fold)
 			rparams[0] = transformed
 			if useRegex {
 				isRegex = true
@@ -239,8 +328,18 @@ func (b Base) RenderParam(e *expr.Expression) (s string, params []any, err error
 		}
 	}
 
+	if e.Op == expr.Like && fold {
+		str, err := withQuestionMarks(func(args []string) (string, error) { return b.renderLikeFold(args[0], args[1], isRegex) }, left, right)
+		return str, params, err
+	}
+
 	if e.Op == expr.Like {
-		str, err := d.RenderLike(left, right, isRegex)
+		str, err := withQuestionMarks(func(args []string) (string, error) { return d.RenderLike(args[0], args[1], isRegex) }, left, right)
+		return str, params, err
+	}
+
+	if e.Op == expr.Equals && fold {
+		str, err := callFN(b.renderEqualsFold, left, right)
 		return str, params, err
 	}
 
@@ -249,12 +348,22 @@ func (b Base) RenderParam(e *expr.Expression) (s string, params []any, err error
 		return s, params, fmt.Errorf("unable to render operator [%s]", e.Op)
 	}
 
-	str, err := fn(left, right)
+	str, err := callFN(fn, left, right)
 	return str, params, err
 }
 
-// Render will render the expression based on the renderFNs provided by the driver.
+// Render will render the expression based on the renderFNs provided by the driver. Boosts are
+// rejected with an error since they never change which rows match; RenderScored accepts them.
 func (b Base) Render(e *expr.Expression) (s string, err error) {
+	e, err = b.resolveBareTerms(e)
+	if err != nil {
+		return "", err
+	}
+	return b.render(e)
+}
+
+// render is Render once the terms without a field are resolved.
+func (b Base) render(e *expr.Expression) (s string, err error) {
 	if e == nil {
 		return "", nil
 	}
@@ -263,6 +372,30 @@ func (b Base) Render(e *expr.Expression) (s string, err error) {
 		return "", fmt.Errorf("null cannot be rendered as a standalone value")
 	}
 
+	if b.relationMatch(e) {
+		return b.renderRelation(e)
+	}
+
+	if e.Op == expr.Fuzzy {
+		return b.renderFuzzy(e)
+	}
+
+	if column, q, ok := b.fullTextQuery(e); ok {
+		return b.renderFullText(column, q)
+	}
+
+	if e.Op == expr.Proximity {
+		inner, err := b.proximityPhrase(e)
+		if err != nil {
+			return "", err
+		}
+		return b.render(inner)
+	}
+
+	if b.arrayMatch(e) {
+		return b.renderArray(e)
+	}
+
 	// Standalone Regexp expression: strip /.../ delimiters and return as a
 	// single-quoted literal. This mirrors what serialize does for nested
 	// Regexp sub-expressions.
@@ -285,9 +418,13 @@ func (b Base) Render(e *expr.Expression) (s string, err error) {
 		return fmt.Sprintf("%s IS NOT NULL", col), nil
 	}
 
+	if column, document, ok := b.jsonContains(e); ok {
+		return b.renderJSONContains(column, document)
+	}
+
 	d := b.dialect()
 
-	left, err := b.serialize(e.Left)
+	left, err := b.serializeLeft(e)
 	if err != nil {
 		return s, err
 	}
@@ -348,22 +485,29 @@ func (b Base) Render(e *expr.Expression) (s string, err error) {
 
 	// Standalone wildcard on a Like operator: `field:*`. Route through the
 	// dialect so each database can decide how to represent "any value".
-	if right == "'*'" && e.Op == expr.Like {
+	if right == d.EscapeStringLiteral("*") && e.Op == expr.Like {
 		return d.RenderStandaloneWild(left)
 	}
 
 	// Detect regex (Lucene /regex/) vs. wildcard and let the dialect transform
 	// the pattern and optionally flip to the regex path. Positioned before
-	// paren-wrap to stay symmetric with RenderParam.
+	// paren-wrap to stay symmetric with RenderParam. The raw pattern is
+	// transformed before it is escaped as a literal, the same order the
+	// parameterized path sees it in, so a dialect whose literals have their
+	// own backslash escapes gets the same pattern either way.
 	isRegex := false
+	fold := b.ignoresCase(e)
 	if e.Op == expr.Like {
-		if rightExpr, ok := e.Right.(*expr.Expression); ok && rightExpr.Op == expr.Regexp {
+		rightExpr, ok := e.Right.(*expr.Expression)
+		if ok && rightExpr.Op == expr.Regexp {
 			isRegex = true
+			if pattern, isStr := rightExpr.Left.(string); fold && isStr {
+				right = d.EscapeStringLiteral(b.prepareRegexpFold(stripRegexpDelimiters(pattern)))
+			}
 		}
-		if !isRegex && len(right) >= 2 && right[0] == '\'' && right[len(right)-1] == '\'' {
-			inner := right[1 : len(right)-1]
-			transformed, useRegex := d.PrepareLikePattern(inner)
-			right = "'" + transformed + "'"
+		if pattern, isStr := likePattern(rightExpr); !isRegex && isStr {
+			transformed, useRegex := b.prepareLikePattern(pattern, fold)
+			right = d.EscapeStringLiteral(transformed)
 			if useRegex {
 				isRegex = true
 			}
@@ -384,10 +528,18 @@ func (b Base) Render(e *expr.Expression) (s string, err error) {
 		}
 	}
 
+	if e.Op == expr.Like && fold {
+		return b.renderLikeFold(left, right, isRegex)
+	}
+
 	if e.Op == expr.Like {
 		return d.RenderLike(left, right, isRegex)
 	}
 
+	if e.Op == expr.Equals && fold {
+		return b.renderEqualsFold(left, right)
+	}
+
 	fn, ok := b.RenderFNs[e.Op]
 	if !ok {
 		return s, fmt.Errorf("unable to render operator [%s]", e.Op)
@@ -400,7 +552,7 @@ func (b Base) isSimple(in any) bool {
 	switch v := in.(type) {
 	case *expr.Expression:
 		return v.Op == expr.Undefined || v.Op == expr.Literal || v.Op == expr.Regexp || v.Op == expr.Wild
-	case expr.Column:
+	case expr.Column, expr.RawColumn:
 		return true
 	case nil:
 		return true
@@ -426,11 +578,11 @@ func (b Base) serialize(in any) (s string, err error) {
 			}
 			return b.dialect().EscapeStringLiteral(s), nil
 		}
-		return b.Render(v)
+		return b.render(v)
 	case []*expr.Expression:
 		strs := []string{}
 		for _, e := range v {
-			s, err = b.Render(e)
+			s, err = b.render(e)
 			if err != nil {
 				return s, err
 			}
@@ -441,11 +593,21 @@ func (b Base) serialize(in any) (s string, err error) {
 		if len(v) == 0 {
 			return "", fmt.Errorf("column name is empty")
 		}
-		return b.dialect().QuoteColumn(string(v))
+		if column, path, ok := b.jsonPath(v); ok {
+			return b.renderJSONPath(column, path, false)
+		}
+		return b.quoteColumn(string(v))
+	case expr.RawColumn:
+		if len(v) == 0 {
+			return "", fmt.Errorf("column name is empty")
+		}
+		return string(v), nil
 	case string:
 		return b.dialect().EscapeStringLiteral(v), nil
 	case bool:
 		return b.dialect().SerializeBool(v), nil
+	case time.Time:
+		return b.serializeTimestamp(v), nil
 	default:
 		return fmt.Sprintf("%v", v), nil
 	}
@@ -464,13 +626,13 @@ func (b Base) serializeParams(in any) (s string, params []any, err error) {
 			if err := validateStringLiteral(s); err != nil {
 				return "", nil, err
 			}
-			return "?", []any{s}, nil
+			return placeholder, []any{s}, nil
 		}
-		return b.RenderParam(v)
+		return b.renderParam(v)
 	case []*expr.Expression:
 		strs := []string{}
 		for _, e := range v {
-			s, eparams, err := b.RenderParam(e)
+			s, eparams, err := b.renderParam(e)
 			if err != nil {
 				return s, params, err
 			}
@@ -482,27 +644,52 @@ func (b Base) serializeParams(in any) (s string, params []any, err error) {
 		if len(v) == 0 {
 			return "", params, fmt.Errorf("column name is empty")
 		}
-		quoted, err := b.dialect().QuoteColumn(string(v))
+		if column, path, ok := b.jsonPath(v); ok {
+			s, err := b.renderJSONPath(column, path, false)
+			return s, params, err
+		}
+		quoted, err := b.quoteColumn(string(v))
 		if err != nil {
 			return "", params, err
 		}
 		return quoted, params, nil
+	case expr.RawColumn:
+		if len(v) == 0 {
+			return "", params, fmt.Errorf("column name is empty")
+		}
+		return string(v), params, nil
 	case bool:
-		return "?", []any{b.dialect().BoolParam(v)}, nil
+		return placeholder, []any{b.dialect().BoolParam(v)}, nil
+	case time.Time:
+		return placeholder, []any{b.timestampParam(v)}, nil
 	case string:
 		// if we have a '*' then we don't want to insert a param since
 		// it can be used either in a regexp or a range operator.
 		if v == "*" {
-			return "'*'", params, nil
+			return b.dialect().EscapeStringLiteral("*"), params, nil
 		}
 
 		// escape single quotes with double single quotes
-		return "?", []any{v}, nil
+		return placeholder, []any{v}, nil
 	default:
-		return "?", []any{v}, nil
+		return placeholder, []any{v}, nil
 	}
 }
 
+func (b Base) serializeTimestamp(t time.Time) string {
+	if d, ok := b.dialect().(TimestampDialect); ok {
+		return d.SerializeTimestamp(t)
+	}
+	return b.dialect().EscapeStringLiteral(t.Format(time.RFC3339Nano))
+}
+
+func (b Base) timestampParam(t time.Time) any {
+	if d, ok := b.dialect().(TimestampDialect); ok {
+		return d.TimestampParam(t)
+	}
+	return t
+}
+
 // extractBoundValue unwraps a range boundary value from its Expression wrapper.
 // Returns the raw Go value (int, float64, string) and whether the bound is unbounded (*).
 func extractBoundValue(bound any) (val any, unbounded bool, err error) {
@@ -528,13 +715,25 @@ func (b Base) formatRangeValue(val any) (string, error) {
 		return strconv.FormatFloat(v, 'f', -1, 64), nil
 	case string:
 		return b.dialect().EscapeStringLiteral(v), nil
+	case time.Time:
+		return b.serializeTimestamp(v), nil
 	case expr.Column:
-		return b.dialect().QuoteColumn(string(v))
+		return b.quoteColumn(string(v))
+	case expr.RawColumn:
+		return string(v), nil
 	default:
 		return fmt.Sprintf("%v", v), nil
 	}
 }
 
+// rangeParam converts a range bound value to its parameter form.
+func (b Base) rangeParam(val any) any {
+	if t, ok := val.(time.Time); ok {
+		return b.timestampParam(t)
+	}
+	return val
+}
+
 // isNumericBound checks whether a range bound value is numeric.
 func isNumericBound(val any) bool {
 	switch val.(type) {
@@ -614,6 +813,7 @@ func (b Base) renderRangeParam(left string, boundary *expr.RangeBoundary) (strin
 		return "", nil, err
 	}
 	inclusive := boundary.Inclusive
+	minVal, maxVal = b.rangeParam(minVal), b.rangeParam(maxVal)
 
 	if minUnbounded && maxUnbounded {
 		return "1=1", nil, nil
@@ -621,27 +821,27 @@ func (b Base) renderRangeParam(left string, boundary *expr.RangeBoundary) (strin
 
 	if minUnbounded {
 		if inclusive {
-			return fmt.Sprintf("%s <= ?", left), []any{maxVal}, nil
+			return fmt.Sprintf("%s <= %s", left, placeholder), []any{maxVal}, nil
 		}
-		return fmt.Sprintf("%s < ?", left), []any{maxVal}, nil
+		return fmt.Sprintf("%s < %s", left, placeholder), []any{maxVal}, nil
 	}
 
 	if maxUnbounded {
 		if inclusive {
-			return fmt.Sprintf("%s >= ?", left), []any{minVal}, nil
+			return fmt.Sprintf("%s >= %s", left, placeholder), []any{minVal}, nil
 		}
-		return fmt.Sprintf("%s > ?", left), []any{minVal}, nil
+		return fmt.Sprintf("%s > %s", left, placeholder), []any{minVal}, nil
 	}
 
 	if isNumericBound(minVal) || isNumericBound(maxVal) {
 		if inclusive {
-			return fmt.Sprintf("%s >= ? AND %s <= ?", left, left), []any{minVal, maxVal}, nil
+			return fmt.Sprintf("%s >= %s AND %s <= %s", left, placeholder, left, placeholder), []any{minVal, maxVal}, nil
 		}
-		return fmt.Sprintf("%s > ? AND %s < ?", left, left), []any{minVal, maxVal}, nil
+		return fmt.Sprintf("%s > %s AND %s < %s", left, placeholder, left, placeholder), []any{minVal, maxVal}, nil
 	}
 
 	if inclusive {
-		return fmt.Sprintf("%s BETWEEN ? AND ?", left), []any{minVal, maxVal}, nil
+		return fmt.Sprintf("%s BETWEEN %s AND %s", left, placeholder, placeholder), []any{minVal, maxVal}, nil
 	}
-	return fmt.Sprintf("%s > ? AND %s < ?", left, left), []any{minVal, maxVal}, nil
+	return fmt.Sprintf("%s > %s AND %s < %s", left, placeholder, left, placeholder), []any{minVal, maxVal}, nil
 }
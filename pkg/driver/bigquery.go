@@ -0,0 +1,197 @@
+package driver
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/grindlemire/go-lucene/pkg/lucene/expr"
+)
+
+// BigQueryDriver transforms a parsed lucene expression to a BigQuery (GoogleSQL) filter.
+//
+// Notable differences from the Postgres driver:
+//   - Identifiers quote with backticks, one path segment at a time, so
+//     a.b renders as `a`.`b` and reaches into a STRUCT column. Names
+//     containing a backtick are rejected at render time.
+//   - Wildcards render as LIKE with backslash escapes for %, _ and \.
+//   - Regex (/pattern/) renders as REGEXP_CONTAINS(col, r'pattern'), which
+//     uses re2. Patterns containing a quote fall back to an escaped literal.
+//   - Standalone `field:*` renders as `field` IS NOT NULL.
+//   - Parameter placeholders are named, @p1, @p2, ... RenderQueryParams
+//     returns them with their names for the query's parameter list.
+//   - String literals escape backslashes and single quotes with a backslash.
+//   - Fuzzy terms use EDIT_DISTANCE.
+type BigQueryDriver struct {
+	Base
+}
+
+// BigQueryParam is a named query parameter. It has the same fields as the
+// client library's bigquery.QueryParameter, so converting is a struct literal
+// without this package depending on the client.
+type BigQueryParam struct {
+	Name  string
+	Value any
+}
+
+// bigqueryPlaceholder writes BigQuery's named @pN placeholders.
+var bigqueryPlaceholder = numbered("@p%d")
+
+// NewBigQueryDriver creates a new driver that will output BigQuery filter strings
+// from parsed lucene expressions.
+func NewBigQueryDriver() BigQueryDriver {
+	fns := map[expr.Operator]RenderFN{}
+	for op, sharedFN := range Shared {
+		fns[op] = sharedFN
+	}
+	return BigQueryDriver{
+		Base: Base{
+			RenderFNs: fns,
+			Dialect:   bigqueryDialect{},
+		},
+	}
+}
+
+// RenderParam will render the expression into a parameterized query using BigQuery's named
+// @pN placeholders. params[i] is the value of p<i+1>.
+func (d BigQueryDriver) RenderParam(e *expr.Expression) (s string, params []any, err error) {
+	return d.renderParamWith(e, bigqueryPlaceholder)
+}
+
+// Finalize returns the SQL and arguments of the fragment with @pN placeholders numbered
+// from start, e.g. for a filter placed after the statement's own @p1 and @p2. args[i] is the
+// value of p<start+i>.
+func (d BigQueryDriver) Finalize(f Fragment, start int) (string, []any, error) {
+	return finalize(f, start, bigqueryPlaceholder)
+}
+
+// RenderScoredParam is Base.RenderScoredParam with @pN placeholders.
+func (d BigQueryDriver) RenderScoredParam(e *expr.Expression) (where string, score string, params []any, err error) {
+	return d.renderScoredParam(e, bigqueryPlaceholder)
+}
+
+// RenderQueryParams is RenderParam with the params named after their placeholders, ready to
+// be set as the Parameters of a bigquery.Query.
+func (d BigQueryDriver) RenderQueryParams(e *expr.Expression) (s string, params []BigQueryParam, err error) {
+	s, values, err := d.RenderParam(e)
+	if err != nil {
+		return "", nil, err
+	}
+
+	for i, v := range values {
+		params = append(params, BigQueryParam{Name: fmt.Sprintf("p%d", i+1), Value: v})
+	}
+	return s, params, nil
+}
+
+// bigqueryDialect implements Dialect for BigQuery.
+type bigqueryDialect struct{}
+
+// RenderLike renders regexes with REGEXP_CONTAINS. A literal pattern is turned
+// back into a raw string so its backslashes read the same as in the query.
+func (bigqueryDialect) RenderLike(left, right string, isRegex bool) (string, error) {
+	if isRegex {
+		return fmt.Sprintf("REGEXP_CONTAINS(%s, %s)", left, bigqueryRawString(right)), nil
+	}
+	return fmt.Sprintf("%s LIKE %s", left, right), nil
+}
+
+// bigqueryRawString converts a literal produced by EscapeStringLiteral to a raw string
+// literal. Placeholders and patterns that can't be written as a raw string are returned
+// unchanged.
+func bigqueryRawString(literal string) string {
+	if len(literal) < 2 || literal[0] != '\'' || literal[len(literal)-1] != '\'' {
+		return literal
+	}
+	raw := strings.NewReplacer(`\\`, `\`, `\'`, `'`).Replace(literal[1 : len(literal)-1])
+	// a raw string can't hold its own quote and a trailing backslash would escape the closing one
+	if strings.Contains(raw, "'") || strings.HasSuffix(raw, `\`) {
+		return literal
+	}
+	return "r'" + raw + "'"
+}
+
+func (bigqueryDialect) RenderStandaloneWild(left string) (string, error) {
+	return fmt.Sprintf("%s IS NOT NULL", left), nil
+}
+
+func (bigqueryDialect) PrepareLikePattern(pattern string) (string, bool) {
+	return translateWildcard(pattern, "%", "_", escapeWith(`\`, `\%_`)), false
+}
+
+func (bigqueryDialect) RenderEqualsFold(left, right string) (string, error) {
+	return fmt.Sprintf("LOWER(%s) = LOWER(%s)", left, right), nil
+}
+
+func (d bigqueryDialect) PrepareLikePatternFold(pattern string) (string, bool) {
+	return d.PrepareLikePattern(pattern)
+}
+
+// PrepareRegexpFold prefixes the pattern with re2's (?i) flag since
+// REGEXP_CONTAINS takes no flags of its own.
+func (bigqueryDialect) PrepareRegexpFold(pattern string) string {
+	return "(?i)" + pattern
+}
+
+// RenderLikeFold lowercases both sides of LIKE since BigQuery has no ILIKE.
+// Regexes already carry their (?i) flag.
+func (d bigqueryDialect) RenderLikeFold(left, right string, isRegex bool) (string, error) {
+	if isRegex {
+		return d.RenderLike(left, right, true)
+	}
+	return fmt.Sprintf("LOWER(%s) LIKE LOWER(%s)", left, right), nil
+}
+
+// EscapeStringLiteral escapes backslashes as well as quotes since BigQuery
+// string literals interpret backslash escape sequences.
+func (bigqueryDialect) EscapeStringLiteral(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `'`, `\'`)
+	return "'" + s + "'"
+}
+
+func (bigqueryDialect) SerializeBool(b bool) string {
+	if b {
+		return "TRUE"
+	}
+	return "FALSE"
+}
+
+func (bigqueryDialect) BoolParam(b bool) any { return b }
+
+// bigqueryTimestampFormat is the TIMESTAMP literal format, always in UTC.
+const bigqueryTimestampFormat = "2006-01-02 15:04:05.999999 UTC"
+
+func (bigqueryDialect) SerializeTimestamp(t time.Time) string {
+	return "TIMESTAMP '" + t.UTC().Format(bigqueryTimestampFormat) + "'"
+}
+
+func (bigqueryDialect) TimestampParam(t time.Time) any { return t }
+
+// RenderFuzzy uses EDIT_DISTANCE, BigQuery's built-in Levenshtein distance.
+func (bigqueryDialect) RenderFuzzy(left, right string, distance int) (string, error) {
+	return Levenshtein("EDIT_DISTANCE")(left, right, distance)
+}
+
+// QuoteColumn quotes each segment of a dotted path separately so nested STRUCT
+// fields resolve.
+func (bigqueryDialect) QuoteColumn(name string) (string, error) {
+	if len(name) == 0 {
+		return "", fmt.Errorf("column name is empty")
+	}
+	if strings.ContainsRune(name, 0) {
+		return "", fmt.Errorf("column name contains a null byte: %q", name)
+	}
+	if strings.ContainsRune(name, '`') {
+		return "", fmt.Errorf("column name contains a backtick: %q", name)
+	}
+
+	segments := strings.Split(name, ".")
+	for i, segment := range segments {
+		if segment == "" {
+			return "", fmt.Errorf("column name has an empty path segment: %q", name)
+		}
+		segments[i] = "`" + segment + "`"
+	}
+	return strings.Join(segments, "."), nil
+}
@@ -0,0 +1,95 @@
+package driver
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/grindlemire/go-lucene/pkg/lucene/expr"
+)
+
+func TestBigQueryDriver(t *testing.T) {
+	type tc struct {
+		input *expr.Expression
+		want  string
+	}
+
+	tcs := map[string]tc{
+		"simple_equals": {
+			input: expr.Eq("a", 5),
+			want:  "`a` = 5",
+		},
+		"struct_path": {
+			input: expr.Eq("user.address.city", "Paris"),
+			want:  "`user`.`address`.`city` = 'Paris'",
+		},
+		"string_escapes": {
+			input: expr.Eq("a", `it's a \ b`),
+			want:  "`a` = 'it\\'s a \\\\ b'",
+		},
+		"bool_literal": {
+			input: expr.Eq("active", true),
+			want:  "`active` = TRUE",
+		},
+		"like_wildcard": {
+			input: expr.LIKE("a", "b*"),
+			want:  "`a` LIKE 'b%'",
+		},
+		"like_escapes_metacharacters": {
+			input: expr.LIKE("a", `100%_?*`),
+			want:  "`a` LIKE '100\\\\%\\\\__%'",
+		},
+		"standalone_wild": {
+			input: expr.LIKE("a", "*"),
+			want:  "`a` IS NOT NULL",
+		},
+		"regexp_raw_string": {
+			input: expr.LIKE("a", expr.REGEXP(`/^b\d+/`)),
+			want:  "REGEXP_CONTAINS(`a`, r'^b\\d+')",
+		},
+		"regexp_with_quote": {
+			input: expr.LIKE("a", expr.REGEXP(`/it's/`)),
+			want:  "REGEXP_CONTAINS(`a`, 'it\\'s')",
+		},
+		"timestamp_range": {
+			input: expr.Rang("t", time.Date(2024, 1, 1, 10, 30, 0, 0, time.UTC), expr.WILD("*"), true),
+			want:  "`t` >= TIMESTAMP '2024-01-01 10:30:00 UTC'",
+		},
+		"fuzzy_edit_distance": {
+			input: expr.FUZZY(expr.Eq("a", "foo"), 2),
+			want:  "EDIT_DISTANCE(`a`, 'foo') <= 2",
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			got, err := NewBigQueryDriver().Render(tc.input)
+			if err != nil {
+				t.Fatalf("got an unexpected error when rendering: %v", err)
+			}
+			if tc.want != got {
+				t.Fatalf(errTemplate, "generated sql does not match", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestBigQueryDriverQueryParams(t *testing.T) {
+	got, params, err := NewBigQueryDriver().RenderQueryParams(
+		expr.AND(expr.Eq("a", "foo"), expr.AND(expr.LIKE("b", "x_*"), expr.LIKE("c", expr.REGEXP("/y+/")))),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "(`a` = @p1) AND ((`b` LIKE @p2) AND (REGEXP_CONTAINS(`c`, @p3)))"; got != want {
+		t.Fatalf(errTemplate, "generated sql does not match", want, got)
+	}
+	want := []BigQueryParam{
+		{Name: "p1", Value: "foo"},
+		{Name: "p2", Value: `x\_%`},
+		{Name: "p3", Value: "y+"},
+	}
+	if !reflect.DeepEqual(params, want) {
+		t.Fatalf("params don't match:\n    wanted %v\n    got    %v", want, params)
+	}
+}
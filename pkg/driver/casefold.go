@@ -0,0 +1,82 @@
+package driver
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/grindlemire/go-lucene/pkg/lucene/expr"
+)
+
+// ignoresCase reports whether an Equals or Like expression should match without regard to case:
+// a regexp with the i flag always does, and any other string match does when the driver or its
+// field is configured to. Equals against numbers, bools and timestamps is never folded.
+func (b Base) ignoresCase(e *expr.Expression) bool {
+	right, ok := e.Right.(*expr.Expression)
+	if !ok {
+		return false
+	}
+
+	switch e.Op {
+	case expr.Equals:
+		if _, isStr := right.Left.(string); !isStr || right.Op != expr.Literal {
+			return false
+		}
+	case expr.Like:
+		if pattern, _ := right.Left.(string); right.Op == expr.Regexp && regexpIgnoresCase(pattern) {
+			return true
+		}
+	default:
+		return false
+	}
+
+	return b.CaseInsensitive || slices.Contains(b.CaseInsensitiveFields, fieldName(e.Left))
+}
+
+// caseInsensitiveDialect returns the dialect's own case-insensitive matching, or nil when it has none.
+func (b Base) caseInsensitiveDialect() CaseInsensitiveDialect {
+	d, _ := b.dialect().(CaseInsensitiveDialect)
+	return d
+}
+
+// prepareLikePattern runs the dialect's PrepareLikePattern, or PrepareLikePatternFold when the
+// match ignores case and the dialect supports it.
+func (b Base) prepareLikePattern(pattern string, fold bool) (string, bool) {
+	if d := b.caseInsensitiveDialect(); fold && d != nil {
+		return d.PrepareLikePatternFold(pattern)
+	}
+	return b.dialect().PrepareLikePattern(pattern)
+}
+
+// prepareRegexpFold flags a case-insensitive regexp pattern for dialects that take the flag in the
+// pattern, leaving it untouched for the others.
+func (b Base) prepareRegexpFold(pattern string) string {
+	if d, ok := b.dialect().(RegexpFoldDialect); ok {
+		return d.PrepareRegexpFold(pattern)
+	}
+	return pattern
+}
+
+// renderLikeFold renders a case-insensitive wildcard or regex match. Dialects without their own
+// support match the lowercased column against the lowercased pattern, which only works for
+// wildcards since lowercasing a regexp would change escapes like \D.
+func (b Base) renderLikeFold(left, right string, isRegex bool) (string, error) {
+	if d := b.caseInsensitiveDialect(); d != nil {
+		return d.RenderLikeFold(left, right, isRegex)
+	}
+	if isRegex {
+		return "", fmt.Errorf("unable to render a case-insensitive regexp: the dialect has no case-insensitive support")
+	}
+	return b.dialect().RenderLike(lower(left), lower(right), false)
+}
+
+// renderEqualsFold renders a case-insensitive equality.
+func (b Base) renderEqualsFold(left, right string) (string, error) {
+	if d := b.caseInsensitiveDialect(); d != nil {
+		return d.RenderEqualsFold(left, right)
+	}
+	return fmt.Sprintf("%s = %s", lower(left), lower(right)), nil
+}
+
+func lower(s string) string {
+	return fmt.Sprintf("LOWER(%s)", s)
+}
@@ -0,0 +1,252 @@
+package driver
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/grindlemire/go-lucene/pkg/lucene/expr"
+)
+
+func TestCaseInsensitive(t *testing.T) {
+	postgres := NewPostgresDriver()
+	postgres.CaseInsensitive = true
+	sqlite := NewSQLiteDriver()
+	sqlite.CaseInsensitive = true
+	mysql := NewMySQLDriver()
+	mysql.CaseInsensitive = true
+	nameOnly := NewPostgresDriver()
+	nameOnly.CaseInsensitiveFields = []string{"name"}
+	oracle := NewOracleDriver()
+	oracle.CaseInsensitive = true
+	clickhouse := NewClickHouseDriver()
+	clickhouse.CaseInsensitive = true
+	bigquery := NewBigQueryDriver()
+	bigquery.CaseInsensitive = true
+	sqlserver := NewSQLServerDriver()
+	sqlserver.CaseInsensitive = true
+
+	type tc struct {
+		driver interface {
+			Render(*expr.Expression) (string, error)
+		}
+		input *expr.Expression
+		want  string
+		err   string
+	}
+
+	tcs := map[string]tc{
+		"postgres_equals": {
+			driver: postgres,
+			input:  expr.Eq("a", "Foo"),
+			want:   `LOWER("a") = LOWER('Foo')`,
+		},
+		"postgres_equals_number_is_exact": {
+			driver: postgres,
+			input:  expr.Eq("a", 5),
+			want:   `"a" = 5`,
+		},
+		"postgres_wildcard": {
+			driver: postgres,
+			input:  expr.LIKE("a", "fo_o*"),
+			want:   `"a" ILIKE 'fo\_o%'`,
+		},
+		"postgres_wildcard_alternation": {
+			driver: postgres,
+			input:  expr.LIKE("a", "*(b|d)*"),
+			want:   `"a" ~* '^(.*(b|d).*)$'`,
+		},
+		"postgres_regexp": {
+			driver: postgres,
+			input:  expr.LIKE("a", expr.REGEXP("/fo+/")),
+			want:   `"a" ~* 'fo+'`,
+		},
+		"postgres_standalone_wild": {
+			driver: postgres,
+			input:  expr.LIKE("a", "*"),
+			want:   `"a" SIMILAR TO '%'`,
+		},
+		"sqlite_equals": {
+			driver: sqlite,
+			input:  expr.Eq("a", "Foo"),
+			want:   `"a" = 'Foo' COLLATE NOCASE`,
+		},
+		"sqlite_wildcard": {
+			driver: sqlite,
+			input:  expr.LIKE("a", "fo%o*"),
+			want:   `LOWER("a") LIKE LOWER('fo#%o%') ESCAPE '#'`,
+		},
+		"sqlite_regexp": {
+			driver: sqlite,
+			input:  expr.LIKE("a", expr.REGEXP("/fo+/")),
+			want:   `"a" REGEXP '(?i)fo+'`,
+		},
+		"mysql_equals": {
+			driver: mysql,
+			input:  expr.Eq("a", "Foo"),
+			want:   "LOWER(`a`) = LOWER('Foo')",
+		},
+		"mysql_wildcard": {
+			driver: mysql,
+			input:  expr.LIKE("a", "fo*"),
+			want:   "LOWER(`a`) LIKE LOWER('fo%') ESCAPE '#'",
+		},
+		"mysql_regexp": {
+			driver: mysql,
+			input:  expr.LIKE("a", expr.REGEXP("/fo+/")),
+			want:   "REGEXP_LIKE(`a`, 'fo+', 'i')",
+		},
+		"field_listed": {
+			driver: nameOnly,
+			input:  expr.AND(expr.Eq("name", "Bob"), expr.Eq("city", "Paris")),
+			want:   `(LOWER("name") = LOWER('Bob')) AND ("city" = 'Paris')`,
+		},
+		"regexp_flag_without_config": {
+			driver: NewPostgresDriver(),
+			input:  expr.LIKE("a", expr.REGEXP("/fo+/i")),
+			want:   `"a" ~* 'fo+'`,
+		},
+		"regexp_flag_sqlite": {
+			driver: NewSQLiteDriver(),
+			input:  expr.LIKE("a", expr.REGEXP("/fo+/i")),
+			want:   `"a" REGEXP '(?i)fo+'`,
+		},
+		"oracle_equals": {
+			driver: oracle,
+			input:  expr.Eq("a", "Foo"),
+			want:   `LOWER("A") = LOWER('Foo')`,
+		},
+		"oracle_wildcard": {
+			driver: oracle,
+			input:  expr.LIKE("a", "fo*"),
+			want:   `LOWER("A") LIKE LOWER('fo%') ESCAPE '\'`,
+		},
+		"oracle_regexp": {
+			driver: oracle,
+			input:  expr.LIKE("a", expr.REGEXP("/fo+/")),
+			want:   `REGEXP_LIKE("A", 'fo+', 'i')`,
+		},
+		"clickhouse_equals": {
+			driver: clickhouse,
+			input:  expr.Eq("a", "Foo"),
+			want:   "lower(`a`) = lower('Foo')",
+		},
+		"clickhouse_wildcard": {
+			driver: clickhouse,
+			input:  expr.LIKE("a", "fo*"),
+			want:   "`a` ILIKE 'fo%'",
+		},
+		"clickhouse_regexp": {
+			driver: clickhouse,
+			input:  expr.LIKE("a", expr.REGEXP("/fo+/")),
+			want:   "match(`a`, '(?i)fo+')",
+		},
+		"bigquery_equals": {
+			driver: bigquery,
+			input:  expr.Eq("a", "Foo"),
+			want:   "LOWER(`a`) = LOWER('Foo')",
+		},
+		"bigquery_wildcard": {
+			driver: bigquery,
+			input:  expr.LIKE("a", "fo*"),
+			want:   "LOWER(`a`) LIKE LOWER('fo%')",
+		},
+		"bigquery_regexp": {
+			driver: bigquery,
+			input:  expr.LIKE("a", expr.REGEXP(`/fo\d/`)),
+			want:   "REGEXP_CONTAINS(`a`, r'(?i)fo\\d')",
+		},
+		"fallback_equals": {
+			driver: sqlserver,
+			input:  expr.Eq("a", "Foo"),
+			want:   "LOWER([a]) = LOWER(N'Foo')",
+		},
+		"fallback_wildcard": {
+			driver: sqlserver,
+			input:  expr.LIKE("a", "fo*"),
+			want:   "LOWER([a]) LIKE LOWER(N'fo%')",
+		},
+		"fallback_regexp_errors": {
+			driver: sqlserver,
+			input:  expr.LIKE("a", expr.REGEXP("/fo+/")),
+			err:    "the dialect has no case-insensitive support",
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			got, err := tc.driver.Render(tc.input)
+			if tc.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Fatalf(errTemplate, "error doesn't match", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("got an unexpected error when rendering: %v", err)
+			}
+			if got != tc.want {
+				t.Fatalf(errTemplate, "generated sql doesn't match", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestCaseInsensitiveParam(t *testing.T) {
+	d := NewPostgresDriver()
+	d.CaseInsensitive = true
+
+	got, params, err := d.RenderParam(expr.AND(expr.Eq("a", "Foo"), expr.LIKE("b", "ba_r*")))
+	if err != nil {
+		t.Fatalf("got an unexpected error when rendering: %v", err)
+	}
+	if want := `(LOWER("a") = LOWER($1)) AND ("b" ILIKE $2)`; got != want {
+		t.Fatalf(errTemplate, "generated sql doesn't match", want, got)
+	}
+	if want := []any{"Foo", `ba\_r%`}; !reflect.DeepEqual(params, want) {
+		t.Fatalf("params don't match:\n    wanted %v\n    got    %v", want, params)
+	}
+}
+
+func TestCaseInsensitiveRegexpParam(t *testing.T) {
+	type tc struct {
+		driver interface {
+			RenderParam(*expr.Expression) (string, []any, error)
+		}
+		want   string
+		params []any
+	}
+
+	tcs := map[string]tc{
+		"sqlite": {
+			driver: NewSQLiteDriver(),
+			want:   `"a" REGEXP ?`,
+			params: []any{"(?i)fo+"},
+		},
+		"clickhouse": {
+			driver: NewClickHouseDriver(),
+			want:   "match(`a`, {p1:String})",
+			params: []any{"(?i)fo+"},
+		},
+		"oracle": {
+			driver: NewOracleDriver(),
+			want:   `REGEXP_LIKE("A", :1, 'i')`,
+			params: []any{"fo+"},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			got, params, err := tc.driver.RenderParam(expr.LIKE("a", expr.REGEXP("/fo+/i")))
+			if err != nil {
+				t.Fatalf("got an unexpected error when rendering: %v", err)
+			}
+			if got != tc.want {
+				t.Fatalf(errTemplate, "generated sql doesn't match", tc.want, got)
+			}
+			if !reflect.DeepEqual(params, tc.params) {
+				t.Fatalf("params don't match:\n    wanted %v\n    got    %v", tc.params, params)
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package driver
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/grindlemire/go-lucene/pkg/lucene/expr"
+)
+
+// ClickHouseDriver transforms a parsed lucene expression to a ClickHouse SQL filter.
+//
+// Notable differences from the Postgres driver:
+//   - Identifiers quote with backticks (`col`). Column names containing a
+//     backtick are rejected at render time.
+//   - Wildcards render as LIKE with backslash escapes for %, _ and \.
+//   - Regex (/pattern/) renders as match(col, pattern), which uses re2.
+//   - Standalone `field:*` renders as isNotNull(`field`).
+//   - Parameter placeholders are typed query parameters, {p1:String},
+//     {p2:Int64}, ..., as used by the HTTP interface (param_p1=...) and
+//     clickhouse-go. The type comes from the go type of the value.
+//   - String literals escape backslashes and single quotes with a backslash.
+//   - Fuzzy terms use editDistance.
+type ClickHouseDriver struct {
+	Base
+}
+
+// NewClickHouseDriver creates a new driver that will output ClickHouse filter strings
+// from parsed lucene expressions.
+func NewClickHouseDriver() ClickHouseDriver {
+	fns := map[expr.Operator]RenderFN{}
+	for op, sharedFN := range Shared {
+		fns[op] = sharedFN
+	}
+	return ClickHouseDriver{
+		Base: Base{
+			RenderFNs: fns,
+			Dialect:   clickhouseDialect{},
+		},
+	}
+}
+
+// RenderParam will render the expression into a parameterized query using ClickHouse's typed
+// {pN:Type} query parameters. params[i] is the value of p<i+1>.
+func (d ClickHouseDriver) RenderParam(e *expr.Expression) (s string, params []any, err error) {
+	return d.renderParamWith(e, clickhousePlaceholder)
+}
+
+// Finalize returns the SQL and arguments of the fragment with {pN:Type} parameters numbered
+// from start, e.g. for a filter placed after the statement's own p1 and p2. args[i] is the
+// value of p<start+i>.
+func (d ClickHouseDriver) Finalize(f Fragment, start int) (string, []any, error) {
+	return finalize(f, start, clickhousePlaceholder)
+}
+
+// RenderScoredParam is Base.RenderScoredParam with typed {pN:Type} parameters.
+func (d ClickHouseDriver) RenderScoredParam(e *expr.Expression) (where string, score string, params []any, err error) {
+	return d.renderScoredParam(e, clickhousePlaceholder)
+}
+
+// clickhousePlaceholder writes a {pN:Type} parameter typed from its value.
+func clickhousePlaceholder(index int, param any) (string, error) {
+	typ, err := clickhouseType(param)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("{p%d:%s}", index, typ), nil
+}
+
+// clickhouseType returns the ClickHouse type of a parameter value.
+func clickhouseType(v any) (string, error) {
+	switch v.(type) {
+	case string:
+		return "String", nil
+	case int:
+		return "Int64", nil
+	case float64:
+		return "Float64", nil
+	case bool:
+		return "Bool", nil
+	case time.Time:
+		return "DateTime64(9, 'UTC')", nil
+	}
+	return "", fmt.Errorf("unable to pick a ClickHouse type for parameter of type %T", v)
+}
+
+// clickhouseDialect implements Dialect for ClickHouse.
+type clickhouseDialect struct{}
+
+func (clickhouseDialect) RenderLike(left, right string, isRegex bool) (string, error) {
+	if isRegex {
+		return fmt.Sprintf("match(%s, %s)", left, right), nil
+	}
+	return fmt.Sprintf("%s LIKE %s", left, right), nil
+}
+
+func (clickhouseDialect) RenderStandaloneWild(left string) (string, error) {
+	return fmt.Sprintf("isNotNull(%s)", left), nil
+}
+
+func (clickhouseDialect) PrepareLikePattern(pattern string) (string, bool) {
+	return translateWildcard(pattern, "%", "_", escapeWith(`\`, `\%_`)), false
+}
+
+func (clickhouseDialect) RenderEqualsFold(left, right string) (string, error) {
+	return fmt.Sprintf("lower(%s) = lower(%s)", left, right), nil
+}
+
+func (d clickhouseDialect) PrepareLikePatternFold(pattern string) (string, bool) {
+	return d.PrepareLikePattern(pattern)
+}
+
+// PrepareRegexpFold prefixes the pattern with re2's (?i) flag since match()
+// takes no flags of its own.
+func (clickhouseDialect) PrepareRegexpFold(pattern string) string {
+	return "(?i)" + pattern
+}
+
+// RenderLikeFold uses ILIKE for wildcards. Regexes already carry their (?i) flag.
+func (d clickhouseDialect) RenderLikeFold(left, right string, isRegex bool) (string, error) {
+	if isRegex {
+		return d.RenderLike(left, right, true)
+	}
+	return fmt.Sprintf("%s ILIKE %s", left, right), nil
+}
+
+// EscapeStringLiteral escapes backslashes as well as quotes since ClickHouse
+// string literals interpret backslash escape sequences.
+func (clickhouseDialect) EscapeStringLiteral(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `'`, `\'`)
+	return "'" + s + "'"
+}
+
+func (clickhouseDialect) SerializeBool(b bool) string {
+	if b {
+		return "true"
+	}
+	return "false"
+}
+
+func (clickhouseDialect) BoolParam(b bool) any { return b }
+
+// clickhouseTimestampFormat is the DateTime64 literal format.
+const clickhouseTimestampFormat = "2006-01-02 15:04:05.999999999"
+
+// SerializeTimestamp renders a UTC DateTime64 so the comparison doesn't depend
+// on the server's time zone.
+func (clickhouseDialect) SerializeTimestamp(t time.Time) string {
+	return fmt.Sprintf("toDateTime64('%s', 9, 'UTC')", t.UTC().Format(clickhouseTimestampFormat))
+}
+
+func (clickhouseDialect) TimestampParam(t time.Time) any { return t }
+
+// RenderFuzzy uses editDistance, ClickHouse's built-in Levenshtein distance.
+func (clickhouseDialect) RenderFuzzy(left, right string, distance int) (string, error) {
+	return Levenshtein("editDistance")(left, right, distance)
+}
+
+func (clickhouseDialect) QuoteColumn(name string) (string, error) {
+	if len(name) == 0 {
+		return "", fmt.Errorf("column name is empty")
+	}
+	if strings.ContainsRune(name, 0) {
+		return "", fmt.Errorf("column name contains a null byte: %q", name)
+	}
+	if strings.ContainsRune(name, '`') {
+		return "", fmt.Errorf("column name contains a backtick: %q", name)
+	}
+	return "`" + name + "`", nil
+}
@@ -0,0 +1,124 @@
+package driver
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/grindlemire/go-lucene/pkg/lucene/expr"
+)
+
+func TestClickHouseDriver(t *testing.T) {
+	type tc struct {
+		input *expr.Expression
+		want  string
+	}
+
+	tcs := map[string]tc{
+		"simple_equals": {
+			input: expr.Eq("a", 5),
+			want:  "`a` = 5",
+		},
+		"string_escapes": {
+			input: expr.Eq("a", `it's a \ b`),
+			want:  "`a` = 'it\\'s a \\\\ b'",
+		},
+		"simple_and": {
+			input: expr.AND(expr.Eq("a", 5), expr.Eq("b", "foo")),
+			want:  "(`a` = 5) AND (`b` = 'foo')",
+		},
+		"bool_literal": {
+			input: expr.Eq("active", true),
+			want:  "`active` = true",
+		},
+		"like_wildcard": {
+			input: expr.LIKE("a", "b*"),
+			want:  "`a` LIKE 'b%'",
+		},
+		"like_escapes_metacharacters": {
+			input: expr.LIKE("a", `100%_?*`),
+			want:  "`a` LIKE '100\\\\%\\\\__%'",
+		},
+		"standalone_wild": {
+			input: expr.LIKE("a", "*"),
+			want:  "isNotNull(`a`)",
+		},
+		"regexp": {
+			input: expr.LIKE("a", expr.REGEXP(`/^b\d+/`)),
+			want:  "match(`a`, '^b\\\\d+')",
+		},
+		"timestamp_range": {
+			input: expr.Rang("t", time.Date(2024, 1, 1, 10, 30, 0, 0, time.UTC), expr.WILD("*"), true),
+			want:  "`t` >= toDateTime64('2024-01-01 10:30:00', 9, 'UTC')",
+		},
+		"fuzzy_edit_distance": {
+			input: expr.FUZZY(expr.Eq("a", "foo"), 2),
+			want:  "editDistance(`a`, 'foo') <= 2",
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			got, err := NewClickHouseDriver().Render(tc.input)
+			if err != nil {
+				t.Fatalf("got an unexpected error when rendering: %v", err)
+			}
+			if tc.want != got {
+				t.Fatalf(errTemplate, "generated sql does not match", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestClickHouseDriverParam(t *testing.T) {
+	ts := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	type tc struct {
+		input      *expr.Expression
+		wantStr    string
+		wantParams []any
+	}
+
+	tcs := map[string]tc{
+		"typed_parameters": {
+			input:      expr.AND(expr.Eq("a", "foo"), expr.Rang("b", 1, 2.5, true)),
+			wantStr:    "(`a` = {p1:String}) AND (`b` >= {p2:Int64} AND `b` <= {p3:Float64})",
+			wantParams: []any{"foo", 1, 2.5},
+		},
+		"bool_param": {
+			input:      expr.Eq("active", true),
+			wantStr:    "`active` = {p1:Bool}",
+			wantParams: []any{true},
+		},
+		"timestamp_param": {
+			input:      expr.GREATER("t", ts),
+			wantStr:    "`t` > {p1:DateTime64(9, 'UTC')}",
+			wantParams: []any{ts},
+		},
+		"like_param": {
+			input:      expr.LIKE("a", `b_*`),
+			wantStr:    "`a` LIKE {p1:String}",
+			wantParams: []any{`b\_%`},
+		},
+		"regexp_param": {
+			input:      expr.LIKE("a", expr.REGEXP("/b+/")),
+			wantStr:    "match(`a`, {p1:String})",
+			wantParams: []any{"b+"},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			gotStr, gotParams, err := NewClickHouseDriver().RenderParam(tc.input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if gotStr != tc.wantStr {
+				t.Fatalf(errTemplate, "generated sql does not match", tc.wantStr, gotStr)
+			}
+			if !reflect.DeepEqual(gotParams, tc.wantParams) {
+				t.Fatalf("params don't match:\n    wanted %v\n    got    %v", tc.wantParams, gotParams)
+			}
+		})
+	}
+}
@@ -1,5 +1,7 @@
 package driver
 
+import "time"
+
 // Dialect captures the operations that differ between SQL databases.
 // Base calls into a Dialect for the operators that have database-specific
 // semantics (Like, Range, standalone wildcard, pattern escaping, bool literals).
@@ -42,6 +44,104 @@ type Dialect interface {
 	QuoteColumn(name string) (string, error)
 }
 
+// TimestampDialect is an optional extension of Dialect for databases that need
+// time.Time values in a specific form. Dialects that don't implement it render
+// timestamps as RFC 3339 string literals and pass time.Time parameters through
+// unchanged.
+type TimestampDialect interface {
+	// SerializeTimestamp converts a time to its SQL literal form.
+	SerializeTimestamp(t time.Time) string
+
+	// TimestampParam returns the parameter value for a time.
+	TimestampParam(t time.Time) any
+}
+
+// FuzzyDialect is an optional extension of Dialect for databases that can
+// match a term approximately (Lucene's field:term~N). Base.Fuzzy takes
+// precedence over it, and fuzzy terms fail to render when neither is set.
+type FuzzyDialect interface {
+	// RenderFuzzy renders a match of the column left against the term right
+	// (a serialized literal or placeholder) within distance edits.
+	RenderFuzzy(left, right string, distance int) (string, error)
+}
+
+// CaseInsensitiveDialect is an optional extension of Dialect for databases with
+// their own case-insensitive matching, used for fields Base is configured to
+// match without case and for /pattern/i regexps. Dialects that don't implement
+// it compare LOWER() of both sides and fail to render case-insensitive regexps.
+type CaseInsensitiveDialect interface {
+	// RenderEqualsFold renders a case-insensitive equality of left and right.
+	RenderEqualsFold(left, right string) (string, error)
+
+	// PrepareLikePatternFold is the case-insensitive form of PrepareLikePattern.
+	PrepareLikePatternFold(pattern string) (transformed string, useRegex bool)
+
+	// RenderLikeFold is the case-insensitive form of RenderLike.
+	RenderLikeFold(left, right string, isRegex bool) (string, error)
+}
+
+// RegexpFoldDialect is an optional extension of CaseInsensitiveDialect for
+// databases whose regexps ignore case through a flag in the pattern itself,
+// such as (?i) in re2. The flag is added to the pattern before it is escaped
+// or bound, so it is part of the value rather than the sql.
+type RegexpFoldDialect interface {
+	// PrepareRegexpFold returns the pattern flagged to ignore case.
+	PrepareRegexpFold(pattern string) string
+}
+
+// FullTextDialect is an optional extension of Dialect for databases with
+// full-text search, used for the fields listed in Base.FullTextFields. Without
+// it those fields fail to render.
+type FullTextDialect interface {
+	// FullTextQuery translates q into the engine's query syntax. The result is
+	// passed to RenderFullText as a literal or placeholder.
+	FullTextQuery(q *FullTextQuery) (string, error)
+
+	// RenderFullText renders a search of the column left for the translated
+	// query right. q is the query right was translated from.
+	RenderFullText(left, right string, q *FullTextQuery) (string, error)
+}
+
+// JSONDialect is an optional extension of Dialect for databases that can read
+// values out of JSON documents, used for fields on the columns listed in
+// Base.JSONColumns. Without it those fields fail to render.
+type JSONDialect interface {
+	// RenderJSONPath renders the value at path inside the JSON document in the
+	// quoted column left. The value is text, so it compares with strings,
+	// unless numeric is set because it is compared with a number.
+	RenderJSONPath(left string, path []string, numeric bool) (string, error)
+}
+
+// JSONContainsDialect is an optional extension of JSONDialect for databases
+// that can match a JSON column against a document it contains, which unlike
+// reading the value can use an index. Equalities on JSON fields render through
+// it when the dialect implements it.
+type JSONContainsDialect interface {
+	// RenderJSONContains renders a match of the JSON document in the quoted
+	// column left against right, a literal or placeholder holding a document
+	// such as {"color":"red"}.
+	RenderJSONContains(left, right string) (string, error)
+}
+
+// ArrayDialect is an optional extension of Dialect for databases with array
+// columns, or JSON arrays standing in for them, used for the fields listed in
+// Base.ArrayFields. Without it those fields fail to render.
+type ArrayDialect interface {
+	// RenderArrayContains renders a match of the array column left holding any
+	// of values, each a literal or placeholder.
+	RenderArrayContains(left string, values []string) (string, error)
+
+	// RenderArrayLength renders the number of elements in the array column
+	// left.
+	RenderArrayLength(left string) (string, error)
+
+	// ArrayElements returns a FROM item over the elements of the array column
+	// left and the expression that reads an element from it. Base matches
+	// elements against wildcards, comparisons and ranges with
+	// EXISTS (SELECT 1 FROM source WHERE ...).
+	ArrayElements(left string) (source, element string)
+}
+
 // defaultDialect is used by Base when no Dialect has been set on a driver
 // (e.g., custom drivers built against the pre-dialect API). It preserves
 // the historical Postgres-flavored behavior that such drivers inherited.
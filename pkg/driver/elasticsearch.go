@@ -0,0 +1,426 @@
+package driver
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/grindlemire/go-lucene/pkg/lucene/expr"
+)
+
+// ElasticsearchDriver transforms a parsed lucene expression into an Elasticsearch (or
+// OpenSearch) Query DSL document. Unlike the sql drivers it does not embed Base since the
+// output is a tree of json objects rather than a string.
+//
+// The mapping to the query DSL is:
+//   - AND, OR and NOT render as bool queries using must, should and must_not. +term and
+//     -term add their clause to must / must_not of the enclosing bool.
+//   - field:value renders as a term query, or a match_phrase query when the value
+//     contains whitespace. A value without a field renders as a multi_match query
+//     against the index's default fields.
+//   - field:pat* renders as a wildcard query and field:/re/ as a regexp query. A phrase
+//     prefix, field:"quick fo"*, renders as a match_phrase_prefix query.
+//   - field:* and field:[* TO *] render as an exists query, field:null as the negation of one.
+//   - ranges and comparisons render as range queries.
+//   - field:(a OR b) renders as a terms query.
+//   - field:value~N renders as a fuzzy query with fuzziness N, field:"a phrase"~N as a
+//     match_phrase query with slop N and ^N sets boost on the wrapped query.
+type ElasticsearchDriver struct{}
+
+// NewElasticsearchDriver creates a new driver that will output Elasticsearch query DSL from
+// parsed lucene expressions.
+func NewElasticsearchDriver() ElasticsearchDriver {
+	return ElasticsearchDriver{}
+}
+
+// Render renders the expression as a json search request body of the form {"query": {...}}
+// that can be sent directly to the _search endpoint.
+func (d ElasticsearchDriver) Render(e *expr.Expression) (string, error) {
+	q, err := d.RenderQuery(e)
+	if err != nil {
+		return "", err
+	}
+
+	out, err := json.Marshal(map[string]any{"query": q})
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// RenderQuery renders the expression as a query DSL clause. The result only contains maps,
+// slices, strings, numbers and bools so it can be embedded in a larger request before being
+// marshalled.
+func (d ElasticsearchDriver) RenderQuery(e *expr.Expression) (map[string]any, error) {
+	if e == nil {
+		return map[string]any{"match_all": map[string]any{}}, nil
+	}
+
+	switch e.Op {
+	case expr.And:
+		return d.renderAnd(e)
+	case expr.Or:
+		return d.renderOr(e)
+	case expr.Not, expr.MustNot:
+		inner, err := d.renderSub(e.Left)
+		if err != nil {
+			return nil, err
+		}
+		return boolQuery("must_not", inner), nil
+	case expr.Must:
+		inner, err := d.renderSub(e.Left)
+		if err != nil {
+			return nil, err
+		}
+		return boolQuery("must", inner), nil
+	case expr.Boost:
+		inner, err := d.renderSub(e.Left)
+		if err != nil {
+			return nil, err
+		}
+		return withBoost(inner, e.BoostPower()), nil
+	case expr.Fuzzy:
+		return d.renderFuzzy(e)
+	case expr.Proximity:
+		return d.renderProximity(e)
+	case expr.Equals:
+		return d.renderEquals(e)
+	case expr.Like:
+		return d.renderLike(e)
+	case expr.Range:
+		return d.renderRange(e)
+	case expr.Greater, expr.GreaterEq, expr.Less, expr.LessEq:
+		return d.renderCompare(e)
+	case expr.In:
+		return d.renderIn(e)
+	case expr.Literal, expr.Wild, expr.Regexp:
+		return d.renderTerm(e)
+	}
+
+	return nil, fmt.Errorf("unable to render operator [%s]", e.Op)
+}
+
+func (d ElasticsearchDriver) renderSub(in any) (map[string]any, error) {
+	e, ok := in.(*expr.Expression)
+	if !ok {
+		return nil, fmt.Errorf("expected a sub expression, got %T", in)
+	}
+	return d.RenderQuery(e)
+}
+
+// renderAnd flattens a chain of ANDs into a single bool query. Must and MustNot children
+// contribute directly to the must and must_not clauses.
+func (d ElasticsearchDriver) renderAnd(e *expr.Expression) (map[string]any, error) {
+	must, mustNot := []any{}, []any{}
+	for _, child := range flatten(e, expr.And) {
+		target, sub := &must, any(child)
+		switch child.Op {
+		case expr.MustNot, expr.Not:
+			target, sub = &mustNot, child.Left
+		case expr.Must:
+			sub = child.Left
+		}
+
+		q, err := d.renderSub(sub)
+		if err != nil {
+			return nil, err
+		}
+		*target = append(*target, q)
+	}
+
+	clauses := map[string]any{}
+	if len(must) > 0 {
+		clauses["must"] = must
+	}
+	if len(mustNot) > 0 {
+		clauses["must_not"] = mustNot
+	}
+	return map[string]any{"bool": clauses}, nil
+}
+
+func (d ElasticsearchDriver) renderOr(e *expr.Expression) (map[string]any, error) {
+	should := []any{}
+	for _, child := range flatten(e, expr.Or) {
+		q, err := d.RenderQuery(child)
+		if err != nil {
+			return nil, err
+		}
+		should = append(should, q)
+	}
+	return map[string]any{
+		"bool": map[string]any{
+			"should":               should,
+			"minimum_should_match": 1,
+		},
+	}, nil
+}
+
+func (d ElasticsearchDriver) renderEquals(e *expr.Expression) (map[string]any, error) {
+	field, err := fieldOf(e.Left)
+	if err != nil {
+		return nil, err
+	}
+
+	right, ok := e.Right.(*expr.Expression)
+	if !ok {
+		return nil, fmt.Errorf("equals requires an expression on the right side, got %T", e.Right)
+	}
+	if right.Op == expr.Null {
+		return boolQuery("must_not", existsQuery(field)), nil
+	}
+
+	if s, isStr := right.Left.(string); isStr && strings.ContainsAny(s, " \t\r\n") {
+		return leafQuery("match_phrase", field, map[string]any{"query": s}), nil
+	}
+	return leafQuery("term", field, map[string]any{"value": right.Left}), nil
+}
+
+func (d ElasticsearchDriver) renderLike(e *expr.Expression) (map[string]any, error) {
+	field, err := fieldOf(e.Left)
+	if err != nil {
+		return nil, err
+	}
+
+	right, ok := e.Right.(*expr.Expression)
+	if !ok {
+		return nil, fmt.Errorf("like requires an expression on the right side, got %T", e.Right)
+	}
+	pattern := fmt.Sprintf("%v", right.Left)
+
+	if right.Op == expr.Regexp {
+		params := map[string]any{"value": stripRegexpDelimiters(pattern)}
+		if regexpIgnoresCase(pattern) {
+			params["case_insensitive"] = true
+		}
+		return leafQuery("regexp", field, params), nil
+	}
+	if pattern == "*" {
+		return existsQuery(field), nil
+	}
+	if prefix, ok := phrasePrefix(pattern); ok {
+		return leafQuery("match_phrase_prefix", field, map[string]any{"query": prefix}), nil
+	}
+	return leafQuery("wildcard", field, map[string]any{"value": pattern}), nil
+}
+
+func (d ElasticsearchDriver) renderRange(e *expr.Expression) (map[string]any, error) {
+	field, err := fieldOf(e.Left)
+	if err != nil {
+		return nil, err
+	}
+
+	boundary, ok := e.Right.(*expr.RangeBoundary)
+	if !ok {
+		return nil, fmt.Errorf("range operator requires *expr.RangeBoundary, got %T", e.Right)
+	}
+	minVal, minUnbounded, err := extractBoundValue(boundary.Min)
+	if err != nil {
+		return nil, err
+	}
+	maxVal, maxUnbounded, err := extractBoundValue(boundary.Max)
+	if err != nil {
+		return nil, err
+	}
+
+	if minUnbounded && maxUnbounded {
+		return existsQuery(field), nil
+	}
+
+	lower, upper := "gt", "lt"
+	if boundary.Inclusive {
+		lower, upper = "gte", "lte"
+	}
+
+	bounds := map[string]any{}
+	if !minUnbounded {
+		bounds[lower] = minVal
+	}
+	if !maxUnbounded {
+		bounds[upper] = maxVal
+	}
+	return leafQuery("range", field, bounds), nil
+}
+
+var esComparisons = map[expr.Operator]string{
+	expr.Greater:   "gt",
+	expr.GreaterEq: "gte",
+	expr.Less:      "lt",
+	expr.LessEq:    "lte",
+}
+
+func (d ElasticsearchDriver) renderCompare(e *expr.Expression) (map[string]any, error) {
+	field, err := fieldOf(e.Left)
+	if err != nil {
+		return nil, err
+	}
+
+	right, ok := e.Right.(*expr.Expression)
+	if !ok {
+		return nil, fmt.Errorf("comparison requires an expression on the right side, got %T", e.Right)
+	}
+	if right.Op == expr.Null {
+		return nil, fmt.Errorf(
+			"comparison operator %s cannot be used with null; use field:null for IS NULL",
+			e.Op,
+		)
+	}
+
+	return leafQuery("range", field, map[string]any{esComparisons[e.Op]: right.Left}), nil
+}
+
+func (d ElasticsearchDriver) renderIn(e *expr.Expression) (map[string]any, error) {
+	field, err := fieldOf(e.Left)
+	if err != nil {
+		return nil, err
+	}
+
+	nonNulls, nullCount, ok := partitionNullsFromList(e.Right)
+	if !ok {
+		return nil, fmt.Errorf("in operator requires a list, got %T", e.Right)
+	}
+
+	values := []any{}
+	for _, v := range nonNulls {
+		values = append(values, v.Left)
+	}
+	terms := map[string]any{"terms": map[string]any{field: values}}
+
+	switch {
+	case nullCount == 0:
+		return terms, nil
+	case len(values) == 0:
+		return boolQuery("must_not", existsQuery(field)), nil
+	}
+	return map[string]any{
+		"bool": map[string]any{
+			"should":               []any{terms, boolQuery("must_not", existsQuery(field))},
+			"minimum_should_match": 1,
+		},
+	}, nil
+}
+
+func (d ElasticsearchDriver) renderFuzzy(e *expr.Expression) (map[string]any, error) {
+	inner, ok := e.Left.(*expr.Expression)
+	if !ok {
+		return nil, fmt.Errorf("fuzzy requires a sub expression, got %T", e.Left)
+	}
+
+	// a bare term without a field fuzzes against the default fields
+	if inner.Op == expr.Literal {
+		return map[string]any{
+			"multi_match": map[string]any{"query": inner.Left, "fuzziness": e.FuzzyDistance()},
+		}, nil
+	}
+
+	if inner.Op != expr.Equals {
+		return nil, fmt.Errorf("fuzzy can only be applied to a term, not %s", inner.Op)
+	}
+	field, err := fieldOf(inner.Left)
+	if err != nil {
+		return nil, err
+	}
+	right, ok := inner.Right.(*expr.Expression)
+	if !ok || right.Op != expr.Literal {
+		return nil, fmt.Errorf("fuzzy can only be applied to a literal value")
+	}
+	return leafQuery("fuzzy", field, map[string]any{"value": right.Left, "fuzziness": e.FuzzyDistance()}), nil
+}
+
+func (d ElasticsearchDriver) renderProximity(e *expr.Expression) (map[string]any, error) {
+	inner, ok := e.Left.(*expr.Expression)
+	if !ok {
+		return nil, fmt.Errorf("proximity requires a sub expression, got %T", e.Left)
+	}
+
+	// a bare phrase without a field matches against the default fields
+	if inner.Op == expr.Literal {
+		return map[string]any{
+			"multi_match": map[string]any{"query": inner.Left, "type": "phrase", "slop": e.Slop()},
+		}, nil
+	}
+
+	if inner.Op != expr.Equals {
+		return nil, fmt.Errorf("proximity can only be applied to a phrase, not %s", inner.Op)
+	}
+	field, err := fieldOf(inner.Left)
+	if err != nil {
+		return nil, err
+	}
+	right, ok := inner.Right.(*expr.Expression)
+	if !ok || right.Op != expr.Literal {
+		return nil, fmt.Errorf("proximity can only be applied to a phrase")
+	}
+	return leafQuery("match_phrase", field, map[string]any{"query": right.Left, "slop": e.Slop()}), nil
+}
+
+// renderTerm renders a value that has no field attached to it.
+func (d ElasticsearchDriver) renderTerm(e *expr.Expression) (map[string]any, error) {
+	switch e.Op {
+	case expr.Wild, expr.Regexp:
+		if prefix, ok := phrasePrefix(fmt.Sprintf("%v", e.Left)); ok && e.Op == expr.Wild {
+			return map[string]any{"multi_match": map[string]any{"query": prefix, "type": "phrase_prefix"}}, nil
+		}
+		// the query_string query is the only one that accepts a pattern without a field
+		return map[string]any{"query_string": map[string]any{"query": fmt.Sprintf("%v", e.Left)}}, nil
+	}
+
+	query := map[string]any{"query": e.Left}
+	if s, isStr := e.Left.(string); isStr && strings.ContainsAny(s, " \t\r\n") {
+		query["type"] = "phrase"
+	}
+	return map[string]any{"multi_match": query}, nil
+}
+
+// flatten collects the operands of a chain of the same binary operator, e.g. (a AND b) AND c
+// returns [a, b, c].
+func flatten(e *expr.Expression, op expr.Operator) []*expr.Expression {
+	if e == nil {
+		return nil
+	}
+	if e.Op != op {
+		return []*expr.Expression{e}
+	}
+
+	out := []*expr.Expression{}
+	for _, side := range []any{e.Left, e.Right} {
+		if sub, ok := side.(*expr.Expression); ok {
+			out = append(out, flatten(sub, op)...)
+		}
+	}
+	return out
+}
+
+func leafQuery(typ, field string, params map[string]any) map[string]any {
+	return map[string]any{typ: map[string]any{field: params}}
+}
+
+func existsQuery(field string) map[string]any {
+	return map[string]any{"exists": map[string]any{"field": field}}
+}
+
+func boolQuery(clause string, q map[string]any) map[string]any {
+	return map[string]any{"bool": map[string]any{clause: []any{q}}}
+}
+
+// withBoost sets the boost on a rendered query. Leaf queries keyed by field carry the boost
+// next to their value while bool, exists and the fieldless queries carry it at the top level.
+func withBoost(q map[string]any, power float64) map[string]any {
+	for typ, body := range q {
+		params, ok := body.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		switch typ {
+		case "bool", "exists", "multi_match", "query_string", "terms":
+			params["boost"] = power
+		default:
+			for _, fieldParams := range params {
+				if fp, ok := fieldParams.(map[string]any); ok {
+					fp["boost"] = power
+				}
+			}
+		}
+	}
+	return q
+}
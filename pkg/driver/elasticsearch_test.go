@@ -0,0 +1,64 @@
+package driver
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/grindlemire/go-lucene/pkg/lucene/expr"
+)
+
+func TestElasticsearchDriver(t *testing.T) {
+	type tc struct {
+		input *expr.Expression
+		want  map[string]any
+	}
+
+	tcs := map[string]tc{
+		"nil_matches_all": {
+			input: nil,
+			want:  map[string]any{"match_all": map[string]any{}},
+		},
+		"raw_column_field": {
+			input: expr.Eq(expr.Lit(expr.RawColumn("user.name")), "bob"),
+			want:  map[string]any{"term": map[string]any{"user.name": map[string]any{"value": "bob"}}},
+		},
+		"nested_or_in_and": {
+			input: expr.AND(expr.Eq("a", 1), expr.OR(expr.Eq("b", 2), expr.Eq("c", 3))),
+			want: map[string]any{"bool": map[string]any{"must": []any{
+				map[string]any{"term": map[string]any{"a": map[string]any{"value": 1}}},
+				map[string]any{"bool": map[string]any{
+					"should": []any{
+						map[string]any{"term": map[string]any{"b": map[string]any{"value": 2}}},
+						map[string]any{"term": map[string]any{"c": map[string]any{"value": 3}}},
+					},
+					"minimum_should_match": 1,
+				}},
+			}}},
+		},
+		"boost_exists": {
+			input: expr.BOOST(expr.LIKE("a", "*"), 2.5),
+			want:  map[string]any{"exists": map[string]any{"field": "a", "boost": 2.5}},
+		},
+		"case_insensitive_regexp": {
+			input: expr.LIKE("a", expr.REGEXP("/fo+/i")),
+			want:  map[string]any{"regexp": map[string]any{"a": map[string]any{"value": "fo+", "case_insensitive": true}}},
+		},
+		"bool_literal": {
+			input: expr.Eq("a", true),
+			want:  map[string]any{"term": map[string]any{"a": map[string]any{"value": true}}},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			got, err := NewElasticsearchDriver().RenderQuery(tc.input)
+			if err != nil {
+				t.Fatalf("got an unexpected error when rendering: %v", err)
+			}
+
+			if !reflect.DeepEqual(tc.want, got) {
+				t.Fatalf("generated query does not match:\n    wanted %v\n    got    %v", tc.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package driver
+
+import (
+	"fmt"
+
+	"github.com/grindlemire/go-lucene/pkg/lucene/expr"
+)
+
+// fieldName returns the name of the column on the left of an operator, or "" when it isn't one.
+func fieldName(in any) string {
+	if e, ok := in.(*expr.Expression); ok && e != nil && e.Op == expr.Literal {
+		in = e.Left
+	}
+	switch v := in.(type) {
+	case expr.Column:
+		return string(v)
+	case expr.RawColumn:
+		return string(v)
+	}
+	return ""
+}
+
+// fieldOf returns the field name of a column expression, for the drivers that build documents
+// rather than sql.
+func fieldOf(in any) (string, error) {
+	e, ok := in.(*expr.Expression)
+	if !ok {
+		return "", fmt.Errorf("expected a field, got %T", in)
+	}
+
+	var field string
+	switch v := e.Left.(type) {
+	case expr.Column:
+		field = string(v)
+	case expr.RawColumn:
+		field = string(v)
+	case string:
+		field = v
+	default:
+		return "", fmt.Errorf("expected a field, got %T", e.Left)
+	}
+
+	if field == "" {
+		return "", fmt.Errorf("column name is empty")
+	}
+	return field, nil
+}
@@ -0,0 +1,117 @@
+package driver
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/grindlemire/go-lucene/pkg/lucene/expr"
+)
+
+// Fragment is a piece of SQL written with ? placeholders and the values for them, in order. A ?
+// that is part of the sql rather than a placeholder, such as the Postgres jsonb operator, is
+// written ??, e.g. "attrs ?? 'color'". A lucene filter rendered with RenderFragment combines with
+// the caller's own fragments through And, Or and Where, and the whole statement is numbered for
+// its driver once with Finalize, so its $N placeholders count up consistently from wherever the
+// statement needs them to start.
+type Fragment struct {
+	SQL  string
+	Args []any
+}
+
+// NewFragment creates a fragment from SQL written with ? placeholders and the values for them.
+func NewFragment(sql string, args ...any) Fragment {
+	return Fragment{SQL: sql, Args: args}
+}
+
+// escapeFragment writes the placeholder markers of sql rendered by renderParam as ? and each ?
+// that is part of the sql as ??, the way fragments are written.
+var escapeFragment = strings.NewReplacer(placeholder, "?", "?", "??")
+
+// markFragment turns the ? placeholders of a fragment's sql into placeholder markers and each ??
+// back into a ?.
+func markFragment(sql string) string {
+	parts := strings.Split(sql, "??")
+	for i, part := range parts {
+		parts[i] = strings.ReplaceAll(part, "?", placeholder)
+	}
+	return strings.Join(parts, "?")
+}
+
+// IsEmpty reports whether the fragment has no SQL, e.g. the filter of an empty query.
+func (f Fragment) IsEmpty() bool {
+	return strings.TrimSpace(f.SQL) == ""
+}
+
+// And combines the fragments into one that matches when all of them do. Empty fragments are
+// skipped.
+func And(fragments ...Fragment) Fragment {
+	return joinFragments(expr.And, fragments)
+}
+
+// Or combines the fragments into one that matches when any of them does. Empty fragments are
+// skipped.
+func Or(fragments ...Fragment) Fragment {
+	return joinFragments(expr.Or, fragments)
+}
+
+func joinFragments(op expr.Operator, fragments []Fragment) Fragment {
+	nonEmpty := []Fragment{}
+	for _, f := range fragments {
+		if !f.IsEmpty() {
+			nonEmpty = append(nonEmpty, f)
+		}
+	}
+	if len(nonEmpty) == 1 {
+		return nonEmpty[0]
+	}
+
+	strs := []string{}
+	args := []any{}
+	for _, f := range nonEmpty {
+		strs = append(strs, "("+f.SQL+")")
+		args = append(args, f.Args...)
+	}
+	return Fragment{SQL: strings.Join(strs, fmt.Sprintf(" %s ", op)), Args: args}
+}
+
+// Where prefixes the fragment with WHERE, leaving it empty when it is, so a query without a
+// filter has no WHERE clause.
+func (f Fragment) Where() Fragment {
+	if f.IsEmpty() {
+		return Fragment{}
+	}
+	return Fragment{SQL: "WHERE " + f.SQL, Args: f.Args}
+}
+
+// RenderFragment renders the expression into a fragment, to be combined with other fragments
+// before it is numbered with the driver's Finalize.
+func (b Base) RenderFragment(e *expr.Expression) (Fragment, error) {
+	e, err := b.resolveBareTerms(e)
+	if err != nil {
+		return Fragment{}, err
+	}
+	s, params, err := b.renderParam(e)
+	if err != nil {
+		return Fragment{}, err
+	}
+	return Fragment{SQL: escapeFragment.Replace(s), Args: params}, nil
+}
+
+// Finalize returns the SQL and arguments of the fragment for a driver with ? placeholders, such
+// as MySQL and SQLite. Drivers with numbered placeholders number them from start, e.g. 3 when the
+// statement already has $1 and $2; ? placeholders aren't numbered, so start is only checked.
+func (b Base) Finalize(f Fragment, start int) (string, []any, error) {
+	return finalize(f, start, questionMarks)
+}
+
+// checkFragment checks that the fragment has a value for each of its placeholders and that the
+// placeholders start from a valid index.
+func checkFragment(f Fragment, start int) error {
+	if start < 1 {
+		return fmt.Errorf("placeholders must start at 1 or more, got %d", start)
+	}
+	if n := strings.Count(markFragment(f.SQL), placeholder); n != len(f.Args) {
+		return fmt.Errorf("fragment has %d placeholders but %d args", n, len(f.Args))
+	}
+	return nil
+}
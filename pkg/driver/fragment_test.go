@@ -0,0 +1,193 @@
+package driver
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/grindlemire/go-lucene/pkg/lucene/expr"
+)
+
+func TestFragment(t *testing.T) {
+	filter := expr.AND(expr.Eq("a", "foo"), expr.Expr("b", expr.Greater, 5))
+
+	type tc struct {
+		driver interface {
+			RenderFragment(*expr.Expression) (Fragment, error)
+			Finalize(Fragment, int) (string, []any, error)
+		}
+		build    func(Fragment) Fragment
+		start    int
+		want     string
+		wantArgs []any
+		err      string
+		noFilter bool
+	}
+
+	tcs := map[string]tc{
+		"postgres_offset": {
+			driver:   NewPostgresDriver(),
+			build:    func(f Fragment) Fragment { return f },
+			start:    3,
+			want:     `("a" = $3) AND ("b" > $4)`,
+			wantArgs: []any{"foo", 5},
+		},
+		"postgres_combined_where": {
+			driver: NewPostgresDriver(),
+			build: func(f Fragment) Fragment {
+				return And(NewFragment(`"tenant_id" = ?`, 7), f).Where()
+			},
+			start:    1,
+			want:     `WHERE ("tenant_id" = $1) AND (("a" = $2) AND ("b" > $3))`,
+			wantArgs: []any{7, "foo", 5},
+		},
+		"postgres_or": {
+			driver: NewPostgresDriver(),
+			build: func(f Fragment) Fragment {
+				return Or(f, NewFragment(`"owner" = ?`, "me"))
+			},
+			start:    2,
+			want:     `(("a" = $2) AND ("b" > $3)) OR ("owner" = $4)`,
+			wantArgs: []any{"foo", 5, "me"},
+		},
+		"empty_filter_is_skipped": {
+			driver:   NewPostgresDriver(),
+			noFilter: true,
+			build: func(f Fragment) Fragment {
+				return And(NewFragment(`"tenant_id" = ?`, 7), f).Where()
+			},
+			start:    1,
+			want:     `WHERE "tenant_id" = $1`,
+			wantArgs: []any{7},
+		},
+		"empty_where": {
+			driver:   NewPostgresDriver(),
+			noFilter: true,
+			build:    func(f Fragment) Fragment { return And(f).Where() },
+			start:    1,
+			want:     "",
+			wantArgs: nil,
+		},
+		"mysql_keeps_question_marks": {
+			driver:   NewMySQLDriver(),
+			build:    func(f Fragment) Fragment { return f.Where() },
+			start:    4,
+			want:     "WHERE (`a` = ?) AND (`b` > ?)",
+			wantArgs: []any{"foo", 5},
+		},
+		"oracle_offset": {
+			driver:   NewOracleDriver(),
+			build:    func(f Fragment) Fragment { return f },
+			start:    2,
+			want:     `("A" = :2) AND ("B" > :3)`,
+			wantArgs: []any{"foo", 5},
+		},
+		"sqlserver_offset": {
+			driver:   NewSQLServerDriver(),
+			build:    func(f Fragment) Fragment { return f },
+			start:    2,
+			want:     `([a] = @p2) AND ([b] > @p3)`,
+			wantArgs: []any{"foo", 5},
+		},
+		"clickhouse_offset": {
+			driver:   NewClickHouseDriver(),
+			build:    func(f Fragment) Fragment { return f },
+			start:    2,
+			want:     "(`a` = {p2:String}) AND (`b` > {p3:Int64})",
+			wantArgs: []any{"foo", 5},
+		},
+		"caller_question_mark_is_not_a_placeholder": {
+			driver: NewPostgresDriver(),
+			build: func(f Fragment) Fragment {
+				return And(NewFragment(`"attrs" ?? 'color'`), NewFragment(`"tenant_id" = ?`, 7), f).Where()
+			},
+			start:    1,
+			want:     `WHERE ("attrs" ? 'color') AND ("tenant_id" = $1) AND (("a" = $2) AND ("b" > $3))`,
+			wantArgs: []any{7, "foo", 5},
+		},
+		"mysql_caller_question_mark": {
+			driver: NewMySQLDriver(),
+			build: func(f Fragment) Fragment {
+				return And(NewFragment("JSON_CONTAINS_PATH(`attrs`, 'one', '$.\"a??\"')"), f)
+			},
+			start:    1,
+			want:     "(JSON_CONTAINS_PATH(`attrs`, 'one', '$.\"a?\"')) AND ((`a` = ?) AND (`b` > ?))",
+			wantArgs: []any{"foo", 5},
+		},
+		"fragment_literal": {
+			driver: NewPostgresDriver(),
+			build: func(f Fragment) Fragment {
+				return And(Fragment{SQL: `"tenant_id" = ?`, Args: []any{7}}, f)
+			},
+			start:    1,
+			want:     `("tenant_id" = $1) AND (("a" = $2) AND ("b" > $3))`,
+			wantArgs: []any{7, "foo", 5},
+		},
+		"bad_start_errors": {
+			driver: NewPostgresDriver(),
+			build:  func(f Fragment) Fragment { return f },
+			start:  0,
+			err:    "placeholders must start at 1 or more",
+		},
+		"missing_arg_errors": {
+			driver: NewPostgresDriver(),
+			build: func(f Fragment) Fragment {
+				return And(f, NewFragment(`"x" = ?`))
+			},
+			start: 1,
+			err:   "fragment has 3 placeholders but 2 args",
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			f := Fragment{}
+			if !tc.noFilter {
+				var err error
+				f, err = tc.driver.RenderFragment(filter)
+				if err != nil {
+					t.Fatalf("got an unexpected error when rendering: %v", err)
+				}
+			}
+
+			got, args, err := tc.driver.Finalize(tc.build(f), tc.start)
+			if tc.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Fatalf(errTemplate, "error doesn't match", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("got an unexpected error when finalizing: %v", err)
+			}
+			if got != tc.want {
+				t.Fatalf(errTemplate, "generated sql doesn't match", tc.want, got)
+			}
+			if !reflect.DeepEqual(args, tc.wantArgs) {
+				t.Fatalf("args don't match:\n    wanted %v\n    got    %v", tc.wantArgs, args)
+			}
+		})
+	}
+}
+
+func TestFragmentRawColumnWithQuestionMark(t *testing.T) {
+	d := NewPostgresDriver()
+	f, err := d.RenderFragment(expr.AND(expr.Eq(expr.RawColumn(`(attrs ? 'color')`), true), expr.Eq("a", "foo")))
+	if err != nil {
+		t.Fatalf("got an unexpected error when rendering: %v", err)
+	}
+	if want := `((attrs ?? 'color') = ?) AND ("a" = ?)`; f.SQL != want {
+		t.Fatalf(errTemplate, "fragment sql doesn't match", want, f.SQL)
+	}
+
+	got, args, err := d.Finalize(And(NewFragment(`"tenant_id" = ?`, 7), f), 1)
+	if err != nil {
+		t.Fatalf("got an unexpected error when finalizing: %v", err)
+	}
+	if want := `("tenant_id" = $1) AND (((attrs ? 'color') = $2) AND ("a" = $3))`; got != want {
+		t.Fatalf(errTemplate, "generated sql doesn't match", want, got)
+	}
+	if want := []any{7, true, "foo"}; !reflect.DeepEqual(args, want) {
+		t.Fatalf("args don't match:\n    wanted %v\n    got    %v", want, args)
+	}
+}
@@ -0,0 +1,303 @@
+package driver
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/grindlemire/go-lucene/pkg/lucene/expr"
+)
+
+// FullTextOp is the kind of a FullTextQuery clause.
+type FullTextOp int
+
+const (
+	// FullTextTerm matches a single word.
+	FullTextTerm FullTextOp = iota
+	// FullTextPhrase matches its words next to each other, in order.
+	FullTextPhrase
+	// FullTextPrefix matches words that start with its text. When the text has more than one
+	// word it is a phrase whose last word is the prefix, e.g. "quick fo"*.
+	FullTextPrefix
+	// FullTextAnd matches when all of its clauses do. Some clauses may be a FullTextNot, but
+	// never all of them.
+	FullTextAnd
+	// FullTextOr matches when any of its clauses do. None of its clauses are a FullTextNot.
+	FullTextOr
+	// FullTextNot excludes the matches of its clause. It only appears in a FullTextAnd.
+	FullTextNot
+	// FullTextNear matches the words of its text in order with up to Slop more positions
+	// between them than in the phrase, e.g. "quick fox"~5.
+	FullTextNear
+)
+
+// FullTextQuery is a full-text search of a single column, built from the lucene clauses on that
+// column. The words of a term, phrase or prefix are in Text and the clauses of an And, Or or Not
+// are in Clauses and the slop of a Near is in Slop. For example body:quick AND body:"brown fox" AND -body:lazy becomes
+//
+//	And(Term("quick"), Phrase("brown fox"), Not(Term("lazy")))
+type FullTextQuery struct {
+	Op      FullTextOp
+	Text    string
+	Slop    int
+	Clauses []*FullTextQuery
+}
+
+// isCompound reports whether the query has to be grouped when it is a clause of another query.
+func (q *FullTextQuery) isCompound() bool {
+	return q.Op == FullTextAnd || q.Op == FullTextOr
+}
+
+// isFullText reports whether the column on the left of an operator is searched with full-text search.
+func (b Base) isFullText(column any) bool {
+	name := fieldName(column)
+	return name != "" && slices.Contains(b.FullTextFields, name)
+}
+
+// fullTextQuery turns the expression into a full-text query when every clause in it is a term,
+// phrase, prefix or proximity match on the same full-text column, e.g. body:quick AND -body:lazy. Negated
+// clauses can only be combined with a clause that isn't, since no engine can search for just
+// the absence of a word, so a lone NOT is left to render as a sql NOT.
+func (b Base) fullTextQuery(e *expr.Expression) (column any, q *FullTextQuery, ok bool) {
+	if e == nil || len(b.FullTextFields) == 0 {
+		return nil, nil, false
+	}
+
+	switch e.Op {
+	case expr.Equals:
+		right, _ := e.Right.(*expr.Expression)
+		if !b.isFullText(e.Left) {
+			return nil, nil, false
+		}
+		q, ok = fullTextTerm(right)
+		return e.Left, q, ok
+	case expr.In:
+		list, _ := e.Right.(*expr.Expression)
+		if !b.isFullText(e.Left) || list == nil || list.Op != expr.List {
+			return nil, nil, false
+		}
+		items, _ := list.Left.([]*expr.Expression)
+		q = &FullTextQuery{Op: FullTextOr}
+		for _, item := range items {
+			term, ok := fullTextTerm(item)
+			if !ok {
+				return nil, nil, false
+			}
+			q.Clauses = append(q.Clauses, term)
+		}
+		return e.Left, q, len(q.Clauses) > 0
+	case expr.Like:
+		right, isExpr := e.Right.(*expr.Expression)
+		if !isExpr || right.Op != expr.Wild || !b.isFullText(e.Left) {
+			return nil, nil, false
+		}
+		pattern, _ := right.Left.(string)
+		prefix, found := strings.CutSuffix(pattern, "*")
+		words := strings.Fields(prefix)
+		if !found || len(words) == 0 || strings.ContainsAny(prefix, `*?\`) || strings.TrimRightFunc(prefix, unicode.IsSpace) != prefix {
+			return nil, nil, false
+		}
+		return e.Left, &FullTextQuery{Op: FullTextPrefix, Text: strings.Join(words, " ")}, true
+	case expr.Proximity:
+		inner, _ := e.Left.(*expr.Expression)
+		if inner == nil || inner.Op != expr.Equals || !b.isFullText(inner.Left) {
+			return nil, nil, false
+		}
+		right, _ := inner.Right.(*expr.Expression)
+		q, ok = fullTextTerm(right)
+		if ok && q.Op == FullTextPhrase && e.Slop() > 0 {
+			q = &FullTextQuery{Op: FullTextNear, Text: q.Text, Slop: e.Slop()}
+		}
+		return inner.Left, q, ok
+	case expr.Must:
+		left, _ := e.Left.(*expr.Expression)
+		return b.fullTextQuery(left)
+	case expr.And, expr.Or:
+		lcol, lq, lok := b.fullTextClause(e.Left, e.Op == expr.And)
+		rcol, rq, rok := b.fullTextClause(e.Right, e.Op == expr.And)
+		if !lok || !rok || fieldName(lcol) != fieldName(rcol) {
+			return nil, nil, false
+		}
+		if lq.Op == FullTextNot && rq.Op == FullTextNot {
+			return nil, nil, false
+		}
+
+		op := FullTextAnd
+		if e.Op == expr.Or {
+			op = FullTextOr
+		}
+		q = &FullTextQuery{Op: op}
+		for _, c := range []*FullTextQuery{lq, rq} {
+			if c.Op == op {
+				q.Clauses = append(q.Clauses, c.Clauses...)
+				continue
+			}
+			q.Clauses = append(q.Clauses, c)
+		}
+		return lcol, q, true
+	}
+	return nil, nil, false
+}
+
+// resolveBareTerms searches the terms without a field, such as quick in "quick AND lang:en", in
+// the full-text field when Base.FullTextFields lists exactly one, the same as parsing the query
+// with that field as the default field. With more than one it can't tell which to search, so
+// it fails rather than render the term as a bare value. Without full-text fields the
+// expression is left as is. The input expression is never modified.
+func (b Base) resolveBareTerms(e *expr.Expression) (*expr.Expression, error) {
+	if e == nil || len(b.FullTextFields) == 0 {
+		return e, nil
+	}
+
+	switch e.Op {
+	case expr.Literal, expr.Wild, expr.Regexp:
+		if len(b.FullTextFields) > 1 {
+			return nil, fmt.Errorf("unable to render the term %s without a field: Base.FullTextFields lists more than one field to search, parse the query with a default field", e)
+		}
+		return expr.Eq(expr.Column(b.FullTextFields[0]), e), nil
+	case expr.And, expr.Or, expr.Not, expr.MustNot, expr.Must, expr.Boost, expr.Fuzzy, expr.Proximity:
+		cp := *e
+		for _, side := range []*any{&cp.Left, &cp.Right} {
+			inner, ok := (*side).(*expr.Expression)
+			if !ok {
+				continue
+			}
+			resolved, err := b.resolveBareTerms(inner)
+			if err != nil {
+				return nil, err
+			}
+			*side = resolved
+		}
+		return &cp, nil
+	}
+	return e, nil
+}
+
+// phrasePrefix returns the phrase of a phrase prefix pattern such as "quick fo*", which matches
+// the words of the phrase with the last one as a prefix (lucene's "quick fo"*).
+func phrasePrefix(pattern string) (string, bool) {
+	prefix, found := strings.CutSuffix(pattern, "*")
+	if !found || strings.ContainsAny(prefix, `*?\`) || len(strings.Fields(prefix)) < 2 {
+		return "", false
+	}
+	return strings.Join(strings.Fields(prefix), " "), true
+}
+
+// fullTextTerm turns a string literal into a term, or a phrase when it has more than one word.
+func fullTextTerm(e *expr.Expression) (*FullTextQuery, bool) {
+	if e == nil || e.Op != expr.Literal {
+		return nil, false
+	}
+	text, _ := e.Left.(string)
+	words := strings.Fields(text)
+	switch len(words) {
+	case 0:
+		return nil, false
+	case 1:
+		return &FullTextQuery{Op: FullTextTerm, Text: words[0]}, true
+	}
+	return &FullTextQuery{Op: FullTextPhrase, Text: strings.Join(words, " ")}, true
+}
+
+// proximityPhrase unwraps a proximity match on a column that isn't searched with full-text search.
+// Only a slop of 0, which is the exact phrase, can be rendered without it.
+func (b Base) proximityPhrase(e *expr.Expression) (*expr.Expression, error) {
+	inner, ok := e.Left.(*expr.Expression)
+	if !ok {
+		return nil, fmt.Errorf("proximity requires a sub expression, got %T", e.Left)
+	}
+	if e.Slop() > 0 {
+		return nil, fmt.Errorf("unable to render operator [%s]: phrase proximity is only supported on full-text fields, add the field to Base.FullTextFields", e.Op)
+	}
+	return inner, nil
+}
+
+// fullTextClause is fullTextQuery for a clause of an And or Or. Clauses of an And may be negated.
+func (b Base) fullTextClause(in any, allowNot bool) (column any, q *FullTextQuery, ok bool) {
+	e, isExpr := in.(*expr.Expression)
+	if !isExpr {
+		return nil, nil, false
+	}
+	if allowNot && (e.Op == expr.Not || e.Op == expr.MustNot) {
+		inner, _ := e.Left.(*expr.Expression)
+		column, q, ok = b.fullTextQuery(inner)
+		if !ok {
+			return nil, nil, false
+		}
+		return column, &FullTextQuery{Op: FullTextNot, Clauses: []*FullTextQuery{q}}, true
+	}
+	return b.fullTextQuery(e)
+}
+
+// fullTextDialect returns the dialect's full-text search, failing when it has none.
+func (b Base) fullTextDialect() (FullTextDialect, error) {
+	d, ok := b.dialect().(FullTextDialect)
+	if !ok {
+		return nil, fmt.Errorf("unable to render full-text search: the dialect has no full-text search support")
+	}
+	return d, nil
+}
+
+func (b Base) renderFullText(column any, q *FullTextQuery) (string, error) {
+	d, err := b.fullTextDialect()
+	if err != nil {
+		return "", err
+	}
+	left, err := b.serialize(column)
+	if err != nil {
+		return "", err
+	}
+	query, err := d.FullTextQuery(q)
+	if err != nil {
+		return "", err
+	}
+	if err := validateStringLiteral(query); err != nil {
+		return "", err
+	}
+	return d.RenderFullText(left, b.dialect().EscapeStringLiteral(query), q)
+}
+
+func (b Base) renderFullTextParam(column any, q *FullTextQuery) (string, []any, error) {
+	d, err := b.fullTextDialect()
+	if err != nil {
+		return "", nil, err
+	}
+	left, params, err := b.serializeParams(column)
+	if err != nil {
+		return "", nil, err
+	}
+	query, err := d.FullTextQuery(q)
+	if err != nil {
+		return "", nil, err
+	}
+	s, err := callFN(func(left, right string) (string, error) { return d.RenderFullText(left, right, q) }, left, placeholder)
+	return s, append(params, query), err
+}
+
+// walkFullText calls fn on the query and each of its clauses, stopping at the first error.
+func walkFullText(q *FullTextQuery, fn func(*FullTextQuery) error) error {
+	if err := fn(q); err != nil {
+		return err
+	}
+	for _, c := range q.Clauses {
+		if err := walkFullText(c, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// joinFullText writes each clause with write, grouping compound clauses in parentheses, and joins
+// them with sep.
+func joinFullText(clauses []*FullTextQuery, sep string, write func(*FullTextQuery) string) string {
+	strs := []string{}
+	for _, c := range clauses {
+		s := write(c)
+		if c.isCompound() {
+			s = "(" + s + ")"
+		}
+		strs = append(strs, s)
+	}
+	return strings.Join(strs, sep)
+}
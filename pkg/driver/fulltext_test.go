@@ -0,0 +1,237 @@
+package driver
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/grindlemire/go-lucene/pkg/lucene/expr"
+)
+
+func TestFullText(t *testing.T) {
+	postgres := NewPostgresDriver()
+	postgres.FullTextFields = []string{"body"}
+	sqlite := NewSQLiteDriver()
+	sqlite.FullTextFields = []string{"body", "posts_fts"}
+	mysql := NewMySQLDriver()
+	mysql.FullTextFields = []string{"body"}
+	oracle := NewOracleDriver()
+	oracle.FullTextFields = []string{"body"}
+
+	// body:quick AND body:"brown fox" AND -body:lazy
+	mixed := expr.AND(
+		expr.AND(expr.Eq("body", "quick"), expr.Eq("body", "brown fox")),
+		expr.MUSTNOT(expr.Eq("body", "lazy")),
+	)
+
+	type tc struct {
+		driver interface {
+			Render(*expr.Expression) (string, error)
+		}
+		input *expr.Expression
+		want  string
+		err   string
+	}
+
+	tcs := map[string]tc{
+		"postgres_term": {
+			driver: postgres,
+			input:  expr.Eq("body", "quick"),
+			want:   `to_tsvector("body") @@ websearch_to_tsquery('quick')`,
+		},
+		"postgres_phrase": {
+			driver: postgres,
+			input:  expr.Eq("body", "brown  fox"),
+			want:   `to_tsvector("body") @@ websearch_to_tsquery('"brown fox"')`,
+		},
+		"postgres_prefix": {
+			driver: postgres,
+			input:  expr.LIKE("body", "qui*"),
+			want:   `to_tsvector("body") @@ to_tsquery('''qui'':*')`,
+		},
+		"postgres_bare_term": {
+			driver: postgres,
+			input:  expr.Lit("quick"),
+			want:   `to_tsvector("body") @@ websearch_to_tsquery('quick')`,
+		},
+		"postgres_bare_prefix": {
+			driver: postgres,
+			input:  expr.WILD("qui*"),
+			want:   `to_tsvector("body") @@ to_tsquery('''qui'':*')`,
+		},
+		"postgres_bare_terms_with_field": {
+			driver: postgres,
+			input:  expr.AND(expr.AND(expr.Lit("quick"), expr.MUSTNOT(expr.Lit("lazy"))), expr.Eq("lang", "en")),
+			want:   `(to_tsvector("body") @@ to_tsquery('''quick'' & !''lazy''')) AND ("lang" = 'en')`,
+		},
+		"sqlite_bare_term_with_two_fields_errors": {
+			driver: sqlite,
+			input:  expr.AND(expr.Eq("body", "quick"), expr.Lit("fox")),
+			err:    "unable to render the term fox without a field",
+		},
+		"postgres_combined": {
+			driver: postgres,
+			input:  mixed,
+			want:   `to_tsvector("body") @@ to_tsquery('''quick'' & ''brown'' <-> ''fox'' & !''lazy''')`,
+		},
+		"postgres_grouped_or": {
+			driver: postgres,
+			input:  expr.AND(expr.OR(expr.Eq("body", "a"), expr.LIKE("body", "b*")), expr.NOT(expr.Eq("body", "c d"))),
+			want:   `to_tsvector("body") @@ to_tsquery('(''a'' | ''b'':*) & !(''c'' <-> ''d'')')`,
+		},
+		"postgres_value_list": {
+			driver: postgres,
+			input:  expr.IN("body", expr.LIST(expr.Lit("quick"), expr.Lit("fox"))),
+			want:   `to_tsvector("body") @@ to_tsquery('''quick'' | ''fox''')`,
+		},
+		"postgres_other_fields_unchanged": {
+			driver: postgres,
+			input:  expr.AND(expr.Eq("body", "quick"), expr.Eq("status", "open")),
+			want:   `(to_tsvector("body") @@ websearch_to_tsquery('quick')) AND ("status" = 'open')`,
+		},
+		"postgres_lone_not": {
+			driver: postgres,
+			input:  expr.MUSTNOT(expr.Eq("body", "lazy")),
+			want:   `NOT(to_tsvector("body") @@ websearch_to_tsquery('lazy'))`,
+		},
+		"postgres_infix_wildcard_is_like": {
+			driver: postgres,
+			input:  expr.LIKE("body", "q*k"),
+			want:   `"body" SIMILAR TO 'q%k'`,
+		},
+		"mysql_combined": {
+			driver: mysql,
+			input:  mixed,
+			want:   "MATCH(`body`) AGAINST('+\"quick\" +\"brown fox\" -\"lazy\"' IN BOOLEAN MODE)",
+		},
+		"mysql_grouped_or": {
+			driver: mysql,
+			input:  expr.AND(expr.OR(expr.Eq("body", "a"), expr.LIKE("body", "b*")), expr.Eq("body", "c")),
+			want:   "MATCH(`body`) AGAINST('+(\"a\" b*) +\"c\"' IN BOOLEAN MODE)",
+		},
+		"sqlite_combined": {
+			driver: sqlite,
+			input:  mixed,
+			want:   `"body" MATCH '("quick" AND "brown fox") NOT "lazy"'`,
+		},
+		"sqlite_prefix_and_or": {
+			driver: sqlite,
+			input:  expr.OR(expr.LIKE("body", "qui*"), expr.Eq("body", `say "hi"`)),
+			want:   `"body" MATCH '"qui"* OR "say ""hi"""'`,
+		},
+		"sqlite_table": {
+			driver: sqlite,
+			input:  expr.Eq("posts_fts", "quick"),
+			want:   `"posts_fts" MATCH '"quick"'`,
+		},
+		"postgres_proximity": {
+			driver: postgres,
+			input:  expr.PROXIMITY(expr.Eq("body", "quick fox"), 2),
+			want:   `to_tsvector("body") @@ to_tsquery('''quick'' <-> ''fox'' | ''quick'' <2> ''fox'' | ''quick'' <3> ''fox''')`,
+		},
+		"postgres_proximity_three_words": {
+			driver: postgres,
+			input:  expr.PROXIMITY(expr.Eq("body", "a b c"), 1),
+			want:   `to_tsvector("body") @@ to_tsquery('''a'' <-> ''b'' <-> ''c'' | ''a'' <-> ''b'' <2> ''c'' | ''a'' <2> ''b'' <-> ''c''')`,
+		},
+		"postgres_proximity_in_and": {
+			driver: postgres,
+			input:  expr.AND(expr.PROXIMITY(expr.Eq("body", "quick fox"), 1), expr.MUSTNOT(expr.PROXIMITY(expr.Eq("body", "lazy dog"), 1))),
+			want:   `to_tsvector("body") @@ to_tsquery('(''quick'' <-> ''fox'' | ''quick'' <2> ''fox'') & !(''lazy'' <-> ''dog'' | ''lazy'' <2> ''dog'')')`,
+		},
+		"postgres_proximity_without_slop": {
+			driver: postgres,
+			input:  expr.PROXIMITY(expr.Eq("body", "quick fox"), 0),
+			want:   `to_tsvector("body") @@ websearch_to_tsquery('"quick fox"')`,
+		},
+		"postgres_proximity_slop_too_large": {
+			driver: postgres,
+			input:  expr.PROXIMITY(expr.Eq("body", "a b c d"), 10),
+			err:    "is too large for a tsquery",
+		},
+		"postgres_phrase_prefix": {
+			driver: postgres,
+			input:  expr.LIKE("body", "quick  fo*"),
+			want:   `to_tsvector("body") @@ to_tsquery('''quick'' <-> ''fo'':*')`,
+		},
+		"postgres_negated_phrase_prefix": {
+			driver: postgres,
+			input:  expr.AND(expr.Eq("body", "a"), expr.NOT(expr.LIKE("body", "b c*"))),
+			want:   `to_tsvector("body") @@ to_tsquery('''a'' & !(''b'' <-> ''c'':*)')`,
+		},
+		"postgres_proximity_other_field_errors": {
+			driver: postgres,
+			input:  expr.PROXIMITY(expr.Eq("title", "quick fox"), 2),
+			err:    "phrase proximity is only supported on full-text fields",
+		},
+		"postgres_proximity_other_field_without_slop": {
+			driver: postgres,
+			input:  expr.PROXIMITY(expr.Eq("title", "quick fox"), 0),
+			want:   `"title" = 'quick fox'`,
+		},
+		"sqlite_proximity": {
+			driver: sqlite,
+			input:  expr.AND(expr.PROXIMITY(expr.Eq("body", `quick "fox"`), 5), expr.MUSTNOT(expr.Eq("body", "lazy"))),
+			want:   `"body" MATCH 'NEAR("quick" """fox""", 5) NOT "lazy"'`,
+		},
+		"sqlite_phrase_prefix": {
+			driver: sqlite,
+			input:  expr.LIKE("body", "quick fo*"),
+			want:   `"body" MATCH '"quick fo"*'`,
+		},
+		"mysql_proximity_errors": {
+			driver: mysql,
+			input:  expr.PROXIMITY(expr.Eq("body", "quick fox"), 5),
+			err:    "mysql has no phrase proximity search",
+		},
+		"mysql_phrase_prefix_errors": {
+			driver: mysql,
+			input:  expr.AND(expr.Eq("body", "a"), expr.LIKE("body", "quick fo*")),
+			err:    "mysql has no phrase prefix search",
+		},
+		"unsupported_dialect_proximity": {
+			driver: oracle,
+			input:  expr.PROXIMITY(expr.Eq("body", "quick fox"), 5),
+			err:    "the dialect has no full-text search support",
+		},
+		"unsupported_dialect": {
+			driver: oracle,
+			input:  expr.Eq("body", "quick"),
+			err:    "the dialect has no full-text search support",
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			got, err := tc.driver.Render(tc.input)
+			if tc.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Fatalf(errTemplate, "error doesn't match", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("got an unexpected error when rendering: %v", err)
+			}
+			if got != tc.want {
+				t.Fatalf(errTemplate, "generated sql doesn't match", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestFullTextParam(t *testing.T) {
+	d := NewPostgresDriver()
+	d.FullTextFields = []string{"body"}
+
+	got, params, err := d.RenderParam(expr.AND(expr.Eq("status", "open"), expr.AND(expr.Eq("body", "quick"), expr.LIKE("body", "fo*"))))
+	if err != nil {
+		t.Fatalf("got an unexpected error when rendering: %v", err)
+	}
+	if want := `("status" = $1) AND (to_tsvector("body") @@ to_tsquery($2))`; got != want {
+		t.Fatalf(errTemplate, "generated sql doesn't match", want, got)
+	}
+	if want := []any{"open", `'quick' & 'fo':*`}; !reflect.DeepEqual(params, want) {
+		t.Fatalf("params don't match:\n    wanted %v\n    got    %v", want, params)
+	}
+}
@@ -0,0 +1,111 @@
+package driver
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/grindlemire/go-lucene/pkg/lucene/expr"
+)
+
+// FuzzyFunc renders a fuzzy match (Lucene's field:term~N) of the column left against the term
+// right, which is a serialized literal or a placeholder, allowing up to distance edits.
+type FuzzyFunc func(left, right string, distance int) (string, error)
+
+// Levenshtein renders fuzzy terms with an edit distance function that takes the column and the
+// term, e.g. Levenshtein("levenshtein") renders a:foo~2 as levenshtein("a", 'foo') <= 2. This is
+// the default for Postgres, where levenshtein comes from the fuzzystrmatch extension, and for
+// SQLite, where a levenshtein function must be registered on the connection. On MySQL pass the
+// name of a user-defined function.
+func Levenshtein(fn string) FuzzyFunc {
+	return func(left, right string, distance int) (string, error) {
+		return fmt.Sprintf("%s(%s, %s) <= %d", fn, left, right, distance), nil
+	}
+}
+
+// Similarity renders fuzzy terms with the pg_trgm similarity function. Trigram similarity is a
+// score rather than an edit count, so the distance is translated to a minimum similarity of
+// 1 / (1 + distance): 0.5 for a:foo~1 and 0.33 for a:foo~2.
+func Similarity() FuzzyFunc {
+	return func(left, right string, distance int) (string, error) {
+		threshold := strconv.FormatFloat(1/float64(1+distance), 'f', 2, 64)
+		return fmt.Sprintf("similarity(%s, %s) >= %s", left, right, threshold), nil
+	}
+}
+
+// Soundex renders fuzzy terms as a phonetic match, SOUNDEX(left) = SOUNDEX(right). It is the
+// default for MySQL, which has no built-in edit distance function. SOUNDEX has no notion of
+// distance, so any distance matches the same terms.
+func Soundex() FuzzyFunc {
+	return func(left, right string, distance int) (string, error) {
+		return fmt.Sprintf("SOUNDEX(%s) = SOUNDEX(%s)", left, right), nil
+	}
+}
+
+// fuzzyFunc returns the FuzzyFunc set on the driver, falling back to the dialect's own fuzzy
+// support. It returns nil when neither supports fuzzy terms.
+func (b Base) fuzzyFunc() FuzzyFunc {
+	if b.Fuzzy != nil {
+		return b.Fuzzy
+	}
+	if d, ok := b.dialect().(FuzzyDialect); ok {
+		return d.RenderFuzzy
+	}
+	return nil
+}
+
+// fuzzyTerm unwraps FUZZY(EQUALS(column, literal)), the only shape a fuzzy term can render from.
+func (b Base) fuzzyTerm(e *expr.Expression) (column any, term *expr.Expression, err error) {
+	if b.fuzzyFunc() == nil {
+		return nil, nil, fmt.Errorf("unable to render operator [%s]: the dialect has no fuzzy support, set Base.Fuzzy to enable it", e.Op)
+	}
+
+	inner, ok := e.Left.(*expr.Expression)
+	if !ok {
+		return nil, nil, fmt.Errorf("fuzzy requires a sub expression, got %T", e.Left)
+	}
+	if inner.Op == expr.Literal {
+		return nil, nil, fmt.Errorf("fuzzy term %s requires a field", inner)
+	}
+	if inner.Op != expr.Equals {
+		return nil, nil, fmt.Errorf("fuzzy can only be applied to a term, not %s", inner.Op)
+	}
+	term, ok = inner.Right.(*expr.Expression)
+	if !ok || term.Op != expr.Literal {
+		return nil, nil, fmt.Errorf("fuzzy can only be applied to a literal value")
+	}
+	return inner.Left, term, nil
+}
+
+func (b Base) renderFuzzy(e *expr.Expression) (string, error) {
+	column, term, err := b.fuzzyTerm(e)
+	if err != nil {
+		return "", err
+	}
+	left, err := b.serialize(column)
+	if err != nil {
+		return "", err
+	}
+	right, err := b.serialize(term)
+	if err != nil {
+		return "", err
+	}
+	return b.fuzzyFunc()(left, right, e.FuzzyDistance())
+}
+
+func (b Base) renderFuzzyParam(e *expr.Expression) (string, []any, error) {
+	column, term, err := b.fuzzyTerm(e)
+	if err != nil {
+		return "", nil, err
+	}
+	left, lparams, err := b.serializeParams(column)
+	if err != nil {
+		return "", nil, err
+	}
+	right, rparams, err := b.serializeParams(term)
+	if err != nil {
+		return "", nil, err
+	}
+	fuzzy := b.fuzzyFunc()
+	s, err := callFN(func(left, right string) (string, error) { return fuzzy(left, right, e.FuzzyDistance()) }, left, right)
+	return s, append(lparams, rparams...), err
+}
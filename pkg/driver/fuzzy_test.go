@@ -0,0 +1,124 @@
+package driver
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/grindlemire/go-lucene/pkg/lucene/expr"
+)
+
+// noFuzzyDialect is a dialect without fuzzy support, like a custom driver built before
+// FuzzyDialect existed. Its RenderFuzzy shadows the embedded one with a different signature
+// so it no longer implements FuzzyDialect.
+type noFuzzyDialect struct{ postgresDialect }
+
+func (noFuzzyDialect) RenderFuzzy() {}
+
+func TestFuzzy(t *testing.T) {
+	postgres := NewPostgresDriver()
+	trigram := NewPostgresDriver()
+	trigram.Fuzzy = Similarity()
+	sqlite := NewSQLiteDriver()
+	mysql := NewMySQLDriver()
+	mysqlUDF := NewMySQLDriver()
+	mysqlUDF.Fuzzy = Levenshtein("levenshtein_udf")
+
+	type tc struct {
+		driver interface {
+			Render(*expr.Expression) (string, error)
+		}
+		input *expr.Expression
+		want  string
+		err   string
+	}
+
+	tcs := map[string]tc{
+		"postgres_levenshtein": {
+			driver: postgres,
+			input:  expr.FUZZY(expr.Eq("a", "foo"), 2),
+			want:   `levenshtein("a", 'foo') <= 2`,
+		},
+		"postgres_similarity": {
+			driver: trigram,
+			input:  expr.FUZZY(expr.Eq("a", "foo"), 1),
+			want:   `similarity("a", 'foo') >= 0.50`,
+		},
+		"postgres_similarity_distance": {
+			driver: trigram,
+			input:  expr.FUZZY(expr.Eq("a", "foo"), 2),
+			want:   `similarity("a", 'foo') >= 0.33`,
+		},
+		"sqlite_levenshtein": {
+			driver: sqlite,
+			input:  expr.FUZZY(expr.Eq("a", "foo"), 1),
+			want:   `levenshtein("a", 'foo') <= 1`,
+		},
+		"mysql_soundex": {
+			driver: mysql,
+			input:  expr.FUZZY(expr.Eq("a", "foo"), 1),
+			want:   "SOUNDEX(`a`) = SOUNDEX('foo')",
+		},
+		"mysql_registered_function": {
+			driver: mysqlUDF,
+			input:  expr.FUZZY(expr.Eq("a", "foo"), 3),
+			want:   "levenshtein_udf(`a`, 'foo') <= 3",
+		},
+		"nested_in_and": {
+			driver: postgres,
+			input:  expr.AND(expr.Eq("b", "bar"), expr.FUZZY(expr.Eq("a", "foo"), 1)),
+			want:   `("b" = 'bar') AND (levenshtein("a", 'foo') <= 1)`,
+		},
+		"custom_dialect_without_fuzzy": {
+			driver: Base{RenderFNs: Shared, Dialect: noFuzzyDialect{}},
+			input:  expr.FUZZY(expr.Eq("a", "foo"), 1),
+			err:    "the dialect has no fuzzy support",
+		},
+		"custom_dialect_with_fuzzy_func": {
+			driver: Base{RenderFNs: Shared, Dialect: noFuzzyDialect{}, Fuzzy: Levenshtein("editdist3")},
+			input:  expr.FUZZY(expr.Eq("a", "foo"), 1),
+			want:   `editdist3("a", 'foo') <= 1`,
+		},
+		"term_without_field": {
+			driver: postgres,
+			input:  expr.FUZZY(expr.Lit("foo"), 1),
+			err:    "requires a field",
+		},
+		"not_a_term": {
+			driver: postgres,
+			input:  expr.FUZZY(expr.LIKE("a", "foo*"), 1),
+			err:    "fuzzy can only be applied to a term",
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			got, err := tc.driver.Render(tc.input)
+			if tc.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Fatalf(errTemplate, "error doesn't match", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("got an unexpected error when rendering: %v", err)
+			}
+			if got != tc.want {
+				t.Fatalf(errTemplate, "generated sql doesn't match", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestFuzzyParam(t *testing.T) {
+	got, params, err := NewPostgresDriver().RenderParam(expr.AND(expr.Eq("b", "bar"), expr.FUZZY(expr.Eq("a", "foo"), 2)))
+	if err != nil {
+		t.Fatalf("got an unexpected error when rendering: %v", err)
+	}
+	if want := `("b" = $1) AND (levenshtein("a", $2) <= 2)`; got != want {
+		t.Fatalf(errTemplate, "generated sql doesn't match", want, got)
+	}
+	if want := []any{"bar", "foo"}; !reflect.DeepEqual(params, want) {
+		t.Fatalf("params don't match:\n    wanted %v\n    got    %v", want, params)
+	}
+}
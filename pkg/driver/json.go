@@ -0,0 +1,184 @@
+package driver
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/grindlemire/go-lucene/pkg/lucene/expr"
+)
+
+// jsonPath splits a field on one of the JSON columns into the column and the keys of the path
+// inside it, e.g. attrs.color on the JSON column attrs is the color key of attrs. Raw columns are
+// rendered as given and are never split.
+func (b Base) jsonPath(in any) (column string, path []string, ok bool) {
+	if e, isExpr := in.(*expr.Expression); isExpr && e != nil && e.Op == expr.Literal {
+		in = e.Left
+	}
+	name, isColumn := in.(expr.Column)
+	if !isColumn {
+		return "", nil, false
+	}
+	for _, c := range b.JSONColumns {
+		if rest, found := strings.CutPrefix(string(name), c+"."); found {
+			return c, strings.Split(rest, "."), true
+		}
+	}
+	return "", nil, false
+}
+
+// jsonDialect returns the dialect's JSON support, failing when it has none.
+func (b Base) jsonDialect(column string, path []string) (JSONDialect, error) {
+	d, ok := b.dialect().(JSONDialect)
+	if !ok {
+		return nil, fmt.Errorf("unable to render field %s.%s: the dialect has no JSON column support", column, strings.Join(path, "."))
+	}
+	for _, key := range path {
+		if key == "" {
+			return nil, fmt.Errorf("unable to render field %s.%s: the path has an empty key", column, strings.Join(path, "."))
+		}
+		if err := validateStringLiteral(key); err != nil {
+			return nil, err
+		}
+	}
+	return d, nil
+}
+
+// renderJSONPath renders the value at the path in the JSON column, as a number when numeric is set.
+func (b Base) renderJSONPath(column string, path []string, numeric bool) (string, error) {
+	d, err := b.jsonDialect(column, path)
+	if err != nil {
+		return "", err
+	}
+	left, err := b.quoteColumn(column)
+	if err != nil {
+		return "", err
+	}
+	return d.RenderJSONPath(left, path, numeric)
+}
+
+// serializeLeft serializes the left side of the operator. A JSON path field is read as a number
+// when the operator compares it with one, so 10 sorts after 9.
+func (b Base) serializeLeft(e *expr.Expression) (string, error) {
+	if column, path, ok := b.jsonPath(e.Left); ok && comparesNumber(e) {
+		return b.renderJSONPath(column, path, true)
+	}
+	return b.serialize(e.Left)
+}
+
+func (b Base) serializeLeftParams(e *expr.Expression) (string, []any, error) {
+	if column, path, ok := b.jsonPath(e.Left); ok && comparesNumber(e) {
+		s, err := b.renderJSONPath(column, path, true)
+		return s, nil, err
+	}
+	return b.serializeParams(e.Left)
+}
+
+// comparesNumber reports whether the operator compares its left side with a number: an equality
+// or comparison with a number, a range with a numeric bound or a list of numbers.
+func comparesNumber(e *expr.Expression) bool {
+	switch e.Op {
+	case expr.Equals, expr.Greater, expr.GreaterEq, expr.Less, expr.LessEq:
+		return isNumberLiteral(e.Right)
+	case expr.Range:
+		boundary, ok := e.Right.(*expr.RangeBoundary)
+		return ok && (isNumberLiteral(boundary.Min) || isNumberLiteral(boundary.Max))
+	case expr.In:
+		items, _, ok := partitionNullsFromList(e.Right)
+		return ok && len(items) > 0 && isNumberLiteral(items[0])
+	}
+	return false
+}
+
+func isNumberLiteral(in any) bool {
+	e, ok := in.(*expr.Expression)
+	if !ok || e == nil || e.Op != expr.Literal {
+		return false
+	}
+	switch e.Left.(type) {
+	case int, float64:
+		return true
+	}
+	return false
+}
+
+// jsonContains turns an equality on a JSON path field into the JSON document it matches, e.g.
+// attrs.color:red into {"color":"red"}, for dialects that match a document against the
+// documents it contains. Case-insensitive and timestamp equalities are left to compare the value.
+func (b Base) jsonContains(e *expr.Expression) (column string, document string, ok bool) {
+	if _, supported := b.dialect().(JSONContainsDialect); !supported || e.Op != expr.Equals || b.ignoresCase(e) {
+		return "", "", false
+	}
+	column, path, ok := b.jsonPath(e.Left)
+	if !ok {
+		return "", "", false
+	}
+	right, _ := e.Right.(*expr.Expression)
+	if right == nil || right.Op != expr.Literal {
+		return "", "", false
+	}
+
+	var value any
+	switch v := right.Left.(type) {
+	case string, int, float64, bool:
+		value = v
+	default:
+		return "", "", false
+	}
+	for i := len(path) - 1; i >= 0; i-- {
+		value = map[string]any{path[i]: value}
+	}
+	out, err := json.Marshal(value)
+	if err != nil {
+		return "", "", false
+	}
+	return column, string(out), true
+}
+
+func (b Base) renderJSONContains(column string, document string) (string, error) {
+	left, err := b.quoteColumn(column)
+	if err != nil {
+		return "", err
+	}
+	if err := validateStringLiteral(document); err != nil {
+		return "", err
+	}
+	return b.dialect().(JSONContainsDialect).RenderJSONContains(left, b.dialect().EscapeStringLiteral(document))
+}
+
+func (b Base) renderJSONContainsParam(column string, document string) (string, []any, error) {
+	left, err := b.quoteColumn(column)
+	if err != nil {
+		return "", nil, err
+	}
+	s, err := callFN(b.dialect().(JSONContainsDialect).RenderJSONContains, left, placeholder)
+	return s, []any{document}, err
+}
+
+// jsonPathString writes the path in the $.key syntax of MySQL and SQLite. Keys that aren't
+// plain identifiers are quoted.
+func jsonPathString(path []string) string {
+	var s strings.Builder
+	s.WriteString("$")
+	for _, key := range path {
+		s.WriteString(".")
+		if isJSONIdentifier(key) {
+			s.WriteString(key)
+			continue
+		}
+		s.WriteString(`"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(key) + `"`)
+	}
+	return s.String()
+}
+
+func isJSONIdentifier(key string) bool {
+	for i, r := range key {
+		switch {
+		case r == '_' || r == '$' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z'):
+		case i > 0 && r >= '0' && r <= '9':
+		default:
+			return false
+		}
+	}
+	return key != ""
+}
@@ -0,0 +1,159 @@
+package driver
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/grindlemire/go-lucene/pkg/lucene/expr"
+)
+
+func TestJSONColumns(t *testing.T) {
+	postgres := NewPostgresDriver()
+	postgres.JSONColumns = []string{"attrs"}
+	mysql := NewMySQLDriver()
+	mysql.JSONColumns = []string{"attrs"}
+	sqlite := NewSQLiteDriver()
+	sqlite.JSONColumns = []string{"attrs"}
+	oracle := NewOracleDriver()
+	oracle.JSONColumns = []string{"attrs"}
+	folded := NewPostgresDriver()
+	folded.JSONColumns = []string{"attrs"}
+	folded.CaseInsensitive = true
+
+	type tc struct {
+		driver interface {
+			Render(*expr.Expression) (string, error)
+		}
+		input *expr.Expression
+		want  string
+		err   string
+	}
+
+	tcs := map[string]tc{
+		"postgres_equals": {
+			driver: postgres,
+			input:  expr.Eq("attrs.color", "red"),
+			want:   `"attrs" @> '{"color":"red"}'`,
+		},
+		"postgres_nested_equals": {
+			driver: postgres,
+			input:  expr.Eq("attrs.size.width", 5),
+			want:   `"attrs" @> '{"size":{"width":5}}'`,
+		},
+		"postgres_quote_in_value": {
+			driver: postgres,
+			input:  expr.Eq("attrs.name", `it's "x"`),
+			want:   `"attrs" @> '{"name":"it''s \"x\""}'`,
+		},
+		"postgres_compare": {
+			driver: postgres,
+			input:  expr.Expr("attrs.size", expr.GreaterEq, 10),
+			want:   `("attrs"->>'size')::numeric >= 10`,
+		},
+		"postgres_range": {
+			driver: postgres,
+			input:  expr.Rang("attrs.size", 1, 5.5, true),
+			want:   `("attrs"->>'size')::numeric >= 1 AND ("attrs"->>'size')::numeric <= 5.5`,
+		},
+		"postgres_string_range": {
+			driver: postgres,
+			input:  expr.Rang("attrs.color", "a", "m", false),
+			want:   `"attrs"->>'color' > 'a' AND "attrs"->>'color' < 'm'`,
+		},
+		"postgres_wildcard": {
+			driver: postgres,
+			input:  expr.LIKE("attrs.a.b", "r*"),
+			want:   `"attrs"->'a'->>'b' SIMILAR TO 'r%'`,
+		},
+		"postgres_null": {
+			driver: postgres,
+			input:  expr.NOT(expr.Eq("attrs.color", expr.NULL())),
+			want:   `"attrs"->>'color' IS NOT NULL`,
+		},
+		"postgres_number_list": {
+			driver: postgres,
+			input:  expr.IN("attrs.n", expr.LIST(expr.Lit(1), expr.Lit(2))),
+			want:   `("attrs"->>'n')::numeric IN (1, 2)`,
+		},
+		"postgres_case_insensitive_reads_value": {
+			driver: folded,
+			input:  expr.Eq("attrs.color", "Red"),
+			want:   `LOWER("attrs"->>'color') = LOWER('Red')`,
+		},
+		"postgres_column_itself": {
+			driver: postgres,
+			input:  expr.AND(expr.Eq("attrs", "x"), expr.Eq("attrsx.y", "z")),
+			want:   `("attrs" = 'x') AND ("attrsx.y" = 'z')`,
+		},
+		"mysql_equals": {
+			driver: mysql,
+			input:  expr.Eq("attrs.color", "red"),
+			want:   "JSON_UNQUOTE(JSON_EXTRACT(`attrs`, '$.color')) = 'red'",
+		},
+		"mysql_compare": {
+			driver: mysql,
+			input:  expr.Rang("attrs.size", 1, "*", true),
+			want:   "JSON_EXTRACT(`attrs`, '$.size') >= 1",
+		},
+		"mysql_quoted_key": {
+			driver: mysql,
+			input:  expr.Eq("attrs.my key", "x"),
+			want:   "JSON_UNQUOTE(JSON_EXTRACT(`attrs`, '$.\"my key\"')) = 'x'",
+		},
+		"sqlite_equals": {
+			driver: sqlite,
+			input:  expr.Eq("attrs.a.b", "red"),
+			want:   `json_extract("attrs", '$.a.b') = 'red'`,
+		},
+		"sqlite_compare": {
+			driver: sqlite,
+			input:  expr.Expr("attrs.size", expr.Less, 3),
+			want:   `json_extract("attrs", '$.size') < 3`,
+		},
+		"empty_key_errors": {
+			driver: postgres,
+			input:  expr.Expr("attrs..size", expr.Less, 3),
+			err:    "the path has an empty key",
+		},
+		"unsupported_dialect": {
+			driver: oracle,
+			input:  expr.Eq("attrs.color", "red"),
+			err:    "the dialect has no JSON column support",
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			got, err := tc.driver.Render(tc.input)
+			if tc.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Fatalf(errTemplate, "error doesn't match", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("got an unexpected error when rendering: %v", err)
+			}
+			if got != tc.want {
+				t.Fatalf(errTemplate, "generated sql doesn't match", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestJSONColumnsParam(t *testing.T) {
+	d := NewPostgresDriver()
+	d.JSONColumns = []string{"attrs"}
+
+	got, params, err := d.RenderParam(expr.AND(expr.Eq("attrs.color", "red"), expr.Expr("attrs.size", expr.Greater, 2)))
+	if err != nil {
+		t.Fatalf("got an unexpected error when rendering: %v", err)
+	}
+	if want := `("attrs" @> $1) AND (("attrs"->>'size')::numeric > $2)`; got != want {
+		t.Fatalf(errTemplate, "generated sql doesn't match", want, got)
+	}
+	if want := []any{`{"color":"red"}`, 2}; !reflect.DeepEqual(params, want) {
+		t.Fatalf("params don't match:\n    wanted %v\n    got    %v", want, params)
+	}
+}
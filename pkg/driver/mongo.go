@@ -0,0 +1,280 @@
+package driver
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/grindlemire/go-lucene/pkg/lucene/expr"
+)
+
+// MongoDriver transforms a parsed lucene expression into a MongoDB query filter. Like the
+// ElasticsearchDriver it does not embed Base since the output is a document rather than a
+// string. Filters only hold maps, slices and plain go values (strings, numbers, bools, nil
+// and time.Time) so they can be passed to the official driver as a bson.M or bson.D
+// without this package depending on it.
+//
+// The mapping to the filter language is:
+//   - AND and OR render as $and and $or. +term renders as the term itself.
+//   - NOT and -term render as a field level $not when the negated filter is an operator
+//     document on a single field, and as $nor otherwise.
+//   - field:value renders as {field: value} and field:null as {field: null}, which matches
+//     documents where the field is null or missing.
+//   - field:pat* renders as an anchored $regex and field:/re/ as an unanchored $regex.
+//   - field:* and field:[* TO *] render as {field: {$exists: true}}.
+//   - ranges and comparisons render as $gt, $gte, $lt and $lte.
+//   - field:(a OR b) renders as $in. null in the list matches null or missing.
+//   - ^N is dropped since filters have no notion of relevance.
+//
+// Fuzzy terms, phrase proximity and terms without a field have no filter equivalent and
+// return an error.
+type MongoDriver struct{}
+
+// NewMongoDriver creates a new driver that will output MongoDB query filters from parsed
+// lucene expressions.
+func NewMongoDriver() MongoDriver {
+	return MongoDriver{}
+}
+
+// Render renders the expression as a json filter document. It is meant for logging and
+// debugging; use RenderFilter to get a filter to pass to the mongo driver.
+func (d MongoDriver) Render(e *expr.Expression) (string, error) {
+	f, err := d.RenderFilter(e)
+	if err != nil {
+		return "", err
+	}
+
+	out, err := json.Marshal(f)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// RenderFilter renders the expression as a MongoDB query filter. A nil expression renders
+// as the empty filter, which matches every document.
+func (d MongoDriver) RenderFilter(e *expr.Expression) (map[string]any, error) {
+	if e == nil {
+		return map[string]any{}, nil
+	}
+
+	switch e.Op {
+	case expr.And:
+		return d.renderCompound(e, "$and")
+	case expr.Or:
+		return d.renderCompound(e, "$or")
+	case expr.Not, expr.MustNot:
+		inner, err := d.renderSub(e.Left)
+		if err != nil {
+			return nil, err
+		}
+		return mongoNegate(inner), nil
+	case expr.Must, expr.Boost:
+		return d.renderSub(e.Left)
+	case expr.Equals:
+		return d.renderEquals(e)
+	case expr.Like:
+		return d.renderLike(e)
+	case expr.Range:
+		return d.renderRange(e)
+	case expr.Greater, expr.GreaterEq, expr.Less, expr.LessEq:
+		return d.renderCompare(e)
+	case expr.In:
+		return d.renderIn(e)
+	case expr.Literal, expr.Wild, expr.Regexp:
+		return nil, fmt.Errorf("term %s requires a field in a mongo filter; set a default field", e)
+	case expr.Fuzzy, expr.Proximity:
+		return nil, fmt.Errorf("unable to render operator [%s]: mongo filters have no equivalent", e.Op)
+	}
+
+	return nil, fmt.Errorf("unable to render operator [%s]", e.Op)
+}
+
+func (d MongoDriver) renderSub(in any) (map[string]any, error) {
+	e, ok := in.(*expr.Expression)
+	if !ok {
+		return nil, fmt.Errorf("expected a sub expression, got %T", in)
+	}
+	return d.RenderFilter(e)
+}
+
+// renderCompound flattens a chain of ANDs or ORs into a single $and or $or.
+func (d MongoDriver) renderCompound(e *expr.Expression, op string) (map[string]any, error) {
+	clauses := []any{}
+	for _, child := range flatten(e, e.Op) {
+		f, err := d.RenderFilter(child)
+		if err != nil {
+			return nil, err
+		}
+		clauses = append(clauses, f)
+	}
+	return map[string]any{op: clauses}, nil
+}
+
+func (d MongoDriver) renderEquals(e *expr.Expression) (map[string]any, error) {
+	field, err := fieldOf(e.Left)
+	if err != nil {
+		return nil, err
+	}
+
+	right, ok := e.Right.(*expr.Expression)
+	if !ok {
+		return nil, fmt.Errorf("equals requires an expression on the right side, got %T", e.Right)
+	}
+	if right.Op == expr.Null {
+		return map[string]any{field: nil}, nil
+	}
+	return map[string]any{field: right.Left}, nil
+}
+
+func (d MongoDriver) renderLike(e *expr.Expression) (map[string]any, error) {
+	field, err := fieldOf(e.Left)
+	if err != nil {
+		return nil, err
+	}
+
+	right, ok := e.Right.(*expr.Expression)
+	if !ok {
+		return nil, fmt.Errorf("like requires an expression on the right side, got %T", e.Right)
+	}
+	pattern := fmt.Sprintf("%v", right.Left)
+
+	if right.Op == expr.Regexp {
+		regex := map[string]any{"$regex": stripRegexpDelimiters(pattern)}
+		if regexpIgnoresCase(pattern) {
+			regex["$options"] = "i"
+		}
+		return map[string]any{field: regex}, nil
+	}
+	if pattern == "*" {
+		return map[string]any{field: map[string]any{"$exists": true}}, nil
+	}
+	return map[string]any{field: map[string]any{"$regex": mongoWildcard(pattern), "$options": "s"}}, nil
+}
+
+func (d MongoDriver) renderRange(e *expr.Expression) (map[string]any, error) {
+	field, err := fieldOf(e.Left)
+	if err != nil {
+		return nil, err
+	}
+
+	boundary, ok := e.Right.(*expr.RangeBoundary)
+	if !ok {
+		return nil, fmt.Errorf("range operator requires *expr.RangeBoundary, got %T", e.Right)
+	}
+	minVal, minUnbounded, err := extractBoundValue(boundary.Min)
+	if err != nil {
+		return nil, err
+	}
+	maxVal, maxUnbounded, err := extractBoundValue(boundary.Max)
+	if err != nil {
+		return nil, err
+	}
+
+	if minUnbounded && maxUnbounded {
+		return map[string]any{field: map[string]any{"$exists": true}}, nil
+	}
+
+	lower, upper := "$gt", "$lt"
+	if boundary.Inclusive {
+		lower, upper = "$gte", "$lte"
+	}
+
+	bounds := map[string]any{}
+	if !minUnbounded {
+		bounds[lower] = minVal
+	}
+	if !maxUnbounded {
+		bounds[upper] = maxVal
+	}
+	return map[string]any{field: bounds}, nil
+}
+
+var mongoComparisons = map[expr.Operator]string{
+	expr.Greater:   "$gt",
+	expr.GreaterEq: "$gte",
+	expr.Less:      "$lt",
+	expr.LessEq:    "$lte",
+}
+
+func (d MongoDriver) renderCompare(e *expr.Expression) (map[string]any, error) {
+	field, err := fieldOf(e.Left)
+	if err != nil {
+		return nil, err
+	}
+
+	right, ok := e.Right.(*expr.Expression)
+	if !ok {
+		return nil, fmt.Errorf("comparison requires an expression on the right side, got %T", e.Right)
+	}
+	if right.Op == expr.Null {
+		return nil, fmt.Errorf(
+			"comparison operator %s cannot be used with null; use field:null for IS NULL",
+			e.Op,
+		)
+	}
+
+	return map[string]any{field: map[string]any{mongoComparisons[e.Op]: right.Left}}, nil
+}
+
+func (d MongoDriver) renderIn(e *expr.Expression) (map[string]any, error) {
+	field, err := fieldOf(e.Left)
+	if err != nil {
+		return nil, err
+	}
+
+	nonNulls, nullCount, ok := partitionNullsFromList(e.Right)
+	if !ok {
+		return nil, fmt.Errorf("in operator requires a list, got %T", e.Right)
+	}
+
+	values := []any{}
+	for _, v := range nonNulls {
+		values = append(values, v.Left)
+	}
+	if nullCount > 0 {
+		values = append(values, nil)
+	}
+	return map[string]any{field: map[string]any{"$in": values}}, nil
+}
+
+// mongoNegate negates a filter. $not only applies to an operator document on a field, so
+// anything else, including an implicit equality like {field: value}, is wrapped in $nor.
+func mongoNegate(f map[string]any) map[string]any {
+	if len(f) == 1 {
+		for field, cond := range f {
+			ops, isDoc := cond.(map[string]any)
+			if !strings.HasPrefix(field, "$") && isDoc && len(ops) > 0 {
+				return map[string]any{field: map[string]any{"$not": ops}}
+			}
+		}
+	}
+	return map[string]any{"$nor": []any{f}}
+}
+
+// mongoWildcard translates a lucene wildcard pattern into an anchored regular expression.
+// * matches any run of characters, ? matches exactly one and a backslash escapes the next
+// character.
+func mongoWildcard(pattern string) string {
+	var b strings.Builder
+	b.WriteString("^")
+	escaped := false
+	for _, r := range pattern {
+		switch {
+		case escaped:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '*':
+			b.WriteString(".*")
+		case r == '?':
+			b.WriteString(".")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	b.WriteString("$")
+	return b.String()
+}
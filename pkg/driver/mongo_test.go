@@ -0,0 +1,96 @@
+package driver
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grindlemire/go-lucene/pkg/lucene/expr"
+)
+
+func TestMongoDriver(t *testing.T) {
+	ts := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	type tc struct {
+		input *expr.Expression
+		want  map[string]any
+		err   string
+	}
+
+	tcs := map[string]tc{
+		"nil_matches_all": {
+			input: nil,
+			want:  map[string]any{},
+		},
+		"raw_column_field": {
+			input: expr.Eq(expr.Lit(expr.RawColumn("user.name")), "bob"),
+			want:  map[string]any{"user.name": "bob"},
+		},
+		"nested_or_in_and": {
+			input: expr.AND(expr.Eq("a", 1), expr.OR(expr.Eq("b", 2), expr.Eq("c", 3))),
+			want: map[string]any{"$and": []any{
+				map[string]any{"a": 1},
+				map[string]any{"$or": []any{
+					map[string]any{"b": 2},
+					map[string]any{"c": 3},
+				}},
+			}},
+		},
+		"not_operator_document": {
+			input: expr.NOT(expr.GREATER("a", 5)),
+			want:  map[string]any{"a": map[string]any{"$not": map[string]any{"$gt": 5}}},
+		},
+		"not_equality": {
+			input: expr.MUSTNOT(expr.Eq("a", "b")),
+			want:  map[string]any{"$nor": []any{map[string]any{"a": "b"}}},
+		},
+		"escaped_wildcard": {
+			input: expr.LIKE("a", expr.WILD(`a.b\*c*`)),
+			want:  map[string]any{"a": map[string]any{"$regex": `^a\.b\*c.*$`, "$options": "s"}},
+		},
+		"case_insensitive_regexp": {
+			input: expr.LIKE("a", expr.REGEXP("/fo+/i")),
+			want:  map[string]any{"a": map[string]any{"$regex": "fo+", "$options": "i"}},
+		},
+		"timestamp_range": {
+			input: expr.Rang("t", ts, expr.WILD("*"), true),
+			want:  map[string]any{"t": map[string]any{"$gte": ts}},
+		},
+		"bool_literal": {
+			input: expr.Eq("a", true),
+			want:  map[string]any{"a": true},
+		},
+		"boost_is_dropped": {
+			input: expr.BOOST(expr.Eq("a", "b"), 2),
+			want:  map[string]any{"a": "b"},
+		},
+		"fuzzy_errors": {
+			input: expr.FUZZY(expr.Eq("a", "b"), 1),
+			err:   "unable to render operator [FUZZY]",
+		},
+		"term_without_field_errors": {
+			input: expr.Lit("b"),
+			err:   "requires a field",
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			got, err := NewMongoDriver().RenderFilter(tc.input)
+			if tc.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Fatalf(errTemplate, "error doesn't match", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("got an unexpected error when rendering: %v", err)
+			}
+
+			if !reflect.DeepEqual(tc.want, got) {
+				t.Fatalf("generated filter does not match:\n    wanted %v\n    got    %v", tc.want, got)
+			}
+		})
+	}
+}
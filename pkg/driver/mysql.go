@@ -3,6 +3,8 @@ package driver
 import (
 	"fmt"
 	"strings"
+	"time"
+	"unicode"
 
 	"github.com/grindlemire/go-lucene/pkg/lucene/expr"
 )
@@ -78,12 +80,7 @@ func (mysqlDialect) PrepareLikePattern(pattern string) (string, bool) {
 // using '#' as the escape character. '\' would collide with MySQL's string-literal
 // backslash meta-escape under default sql_mode.
 func luceneWildcardToLike(pattern string) string {
-	pattern = strings.ReplaceAll(pattern, `#`, `##`)
-	pattern = strings.ReplaceAll(pattern, `%`, `#%`)
-	pattern = strings.ReplaceAll(pattern, `_`, `#_`)
-	pattern = strings.ReplaceAll(pattern, `*`, `%`)
-	pattern = strings.ReplaceAll(pattern, `?`, `_`)
-	return pattern
+	return translateWildcard(pattern, "%", "_", escapeWith("#", "#%_"))
 }
 
 // luceneWildcardToRegex translates a Lucene wildcard pattern (which may
@@ -103,28 +100,33 @@ func luceneWildcardToRegex(pattern string) string {
 	var b strings.Builder
 	b.Grow(len(pattern) + 8)
 	b.WriteString("^(")
-	for _, r := range pattern {
-		switch r {
-		case '*':
-			b.WriteString(".*")
-		case '?':
-			b.WriteByte('.')
-		// SIMILAR TO metacharacters we retain as regex metacharacters.
-		case '|', '(', ')', '[', ']', '{', '}', '+':
-			b.WriteRune(r)
-		// Regex-only metacharacters escaped so they behave like literals
-		// (matching the SIMILAR TO semantics of the Postgres path).
-		case '.', '^', '$', '\\':
-			b.WriteByte('\\')
-			b.WriteRune(r)
-		default:
-			b.WriteRune(r)
-		}
-	}
+	// SIMILAR TO metacharacters (|()[]{}+) are retained as regex metacharacters,
+	// regex-only ones are escaped so they behave like literals (matching the
+	// SIMILAR TO semantics of the Postgres path), as are an escaped * or ?.
+	b.WriteString(translateWildcard(pattern, ".*", ".", escapeWith(`\`, `.^$\*?`)))
 	b.WriteString(")$")
 	return b.String()
 }
 
+// RenderEqualsFold lowercases both sides rather than relying on the column's
+// collation, which may be case-sensitive (e.g. utf8mb4_bin).
+func (mysqlDialect) RenderEqualsFold(left, right string) (string, error) {
+	return fmt.Sprintf("LOWER(%s) = LOWER(%s)", left, right), nil
+}
+
+func (d mysqlDialect) PrepareLikePatternFold(pattern string) (string, bool) {
+	return d.PrepareLikePattern(pattern)
+}
+
+// RenderLikeFold uses REGEXP_LIKE's i match type for regexes, which needs
+// MySQL 8.0 or MariaDB 10.0.5.
+func (mysqlDialect) RenderLikeFold(left, right string, isRegex bool) (string, error) {
+	if isRegex {
+		return fmt.Sprintf("REGEXP_LIKE(%s, %s, 'i')", left, right), nil
+	}
+	return fmt.Sprintf("LOWER(%s) LIKE LOWER(%s) ESCAPE '#'", left, right), nil
+}
+
 func (mysqlDialect) EscapeStringLiteral(s string) string {
 	s = strings.ReplaceAll(s, `\`, `\\`)
 	s = strings.ReplaceAll(s, `'`, `''`)
@@ -142,6 +144,114 @@ func (mysqlDialect) SerializeBool(b bool) string {
 // bool parameters to the wire protocol's integer form automatically.
 func (mysqlDialect) BoolParam(b bool) any { return b }
 
+// mysqlTimestampFormat is the DATETIME literal format. MySQL doesn't accept an
+// RFC 3339 zone suffix, so times are converted to UTC first.
+const mysqlTimestampFormat = "2006-01-02 15:04:05.999999"
+
+func (mysqlDialect) SerializeTimestamp(t time.Time) string {
+	return "'" + t.UTC().Format(mysqlTimestampFormat) + "'"
+}
+
+// TimestampParam passes the time through directly. go-sql-driver/mysql formats
+// time.Time parameters in the connection's loc.
+func (mysqlDialect) TimestampParam(t time.Time) any { return t }
+
+// RenderFuzzy falls back to SOUNDEX since MySQL has no built-in edit distance
+// function. Set Base.Fuzzy to Levenshtein with the name of a user-defined
+// function to honor the distance.
+func (mysqlDialect) RenderFuzzy(left, right string, distance int) (string, error) {
+	return Soundex()(left, right, distance)
+}
+
+// FullTextQuery fails on proximity matches and phrase prefixes, which boolean
+// mode has no way to write.
+func (mysqlDialect) FullTextQuery(q *FullTextQuery) (string, error) {
+	err := walkFullText(q, func(c *FullTextQuery) error {
+		switch {
+		case c.Op == FullTextNear:
+			return fmt.Errorf("unable to render full-text search: mysql has no phrase proximity search")
+		case c.Op == FullTextPrefix && strings.ContainsFunc(c.Text, unicode.IsSpace):
+			return fmt.Errorf("unable to render full-text search: mysql has no phrase prefix search")
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return mysqlBooleanQuery(q), nil
+}
+
+// RenderFullText searches in boolean mode, which needs a FULLTEXT index over
+// exactly the columns being matched.
+func (mysqlDialect) RenderFullText(left, right string, q *FullTextQuery) (string, error) {
+	return fmt.Sprintf("MATCH(%s) AGAINST(%s IN BOOLEAN MODE)", left, right), nil
+}
+
+// mysqlBooleanOperators are the characters with meaning in a boolean mode
+// search. They can't be escaped, so they are dropped from prefixes.
+const mysqlBooleanOperators = `+-<>()~*"@`
+
+// mysqlBooleanQuery writes the query in boolean mode syntax. Clauses of an AND
+// are marked required with + or excluded with -, clauses of an OR are left
+// unmarked, and terms are quoted so punctuation in them isn't read as an
+// operator.
+func mysqlBooleanQuery(q *FullTextQuery) string {
+	switch q.Op {
+	case FullTextTerm, FullTextPhrase:
+		return `"` + strings.ReplaceAll(q.Text, `"`, " ") + `"`
+	case FullTextPrefix:
+		return strings.Map(func(r rune) rune {
+			if strings.ContainsRune(mysqlBooleanOperators, r) {
+				return -1
+			}
+			return r
+		}, q.Text) + "*"
+	case FullTextOr:
+		return joinFullText(q.Clauses, " ", mysqlBooleanQuery)
+	case FullTextNot:
+		return "-" + joinFullText(q.Clauses, "", mysqlBooleanQuery)
+	}
+
+	clauses := []string{}
+	for _, c := range q.Clauses {
+		if c.Op == FullTextNot {
+			clauses = append(clauses, mysqlBooleanQuery(c))
+			continue
+		}
+		clauses = append(clauses, "+"+joinFullText([]*FullTextQuery{c}, "", mysqlBooleanQuery))
+	}
+	return strings.Join(clauses, " ")
+}
+
+// RenderJSONPath unquotes the extracted value so it compares as text. Numbers
+// are left as JSON, which MySQL compares numerically with SQL numbers.
+func (d mysqlDialect) RenderJSONPath(left string, path []string, numeric bool) (string, error) {
+	extract := fmt.Sprintf("JSON_EXTRACT(%s, %s)", left, d.EscapeStringLiteral(jsonPathString(path)))
+	if numeric {
+		return extract, nil
+	}
+	return fmt.Sprintf("JSON_UNQUOTE(%s)", extract), nil
+}
+
+// RenderArrayContains matches a JSON array column with MEMBER OF for a single
+// value and JSON_OVERLAPS for a list, both of which a multi-valued index on the
+// column can serve. They need MySQL 8.0.17 or later.
+func (mysqlDialect) RenderArrayContains(left string, values []string) (string, error) {
+	if len(values) == 1 {
+		return fmt.Sprintf("%s MEMBER OF(%s)", values[0], left), nil
+	}
+	return fmt.Sprintf("JSON_OVERLAPS(%s, JSON_ARRAY(%s))", left, strings.Join(values, ", ")), nil
+}
+
+func (mysqlDialect) RenderArrayLength(left string) (string, error) {
+	return fmt.Sprintf("JSON_LENGTH(%s)", left), nil
+}
+
+// ArrayElements reads the elements of a JSON array column as text with JSON_TABLE.
+func (mysqlDialect) ArrayElements(left string) (source, element string) {
+	return fmt.Sprintf("JSON_TABLE(%s, '$[*]' COLUMNS (elem TEXT PATH '$')) AS elems", left), "elem"
+}
+
 func (mysqlDialect) QuoteColumn(name string) (string, error) {
 	if len(name) == 0 {
 		return "", fmt.Errorf("column name is empty")
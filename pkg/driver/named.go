@@ -0,0 +1,73 @@
+package driver
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+
+	"github.com/grindlemire/go-lucene/pkg/lucene/expr"
+)
+
+// NamedStyle writes the placeholder for a named parameter.
+type NamedStyle func(name string) string
+
+var (
+	// ColonNamed writes :name placeholders, as used by sqlx, Oracle and SQLite.
+	ColonNamed NamedStyle = func(name string) string { return ":" + name }
+	// AtNamed writes @name placeholders, as used by pgx.NamedArgs, SQL Server, BigQuery and SQLite.
+	AtNamed NamedStyle = func(name string) string { return "@" + name }
+	// DollarNamed writes $name placeholders, as used by SQLite.
+	DollarNamed NamedStyle = func(name string) string { return "$" + name }
+)
+
+// RenderNamed renders the expression into a query with named placeholders written by style
+// and returns the values as sql.NamedArg, ready for database/sql (which takes them as
+// ...any) or sqlx. Parameters are named p1, p2, ... in the order they first appear and
+// identical values share a name, so a:foo OR b:foo renders as ("a" = :p1) OR ("b" = :p1)
+// with a single argument.
+func (b Base) RenderNamed(e *expr.Expression, style NamedStyle) (s string, args []sql.NamedArg, err error) {
+	e, err = b.resolveBareTerms(e)
+	if err != nil {
+		return "", nil, err
+	}
+	str, params, err := b.renderParam(e)
+	if err != nil {
+		return "", nil, err
+	}
+
+	names := map[any]string{}
+	s, _, err = writePlaceholders(str, params, 1, func(_ int, value any) (string, error) {
+		dedupe := value != nil && reflect.TypeOf(value).Comparable()
+		name, seen := "", false
+		if dedupe {
+			name, seen = names[value]
+		}
+		if !seen {
+			name = fmt.Sprintf("p%d", len(args)+1)
+			args = append(args, sql.Named(name, value))
+			if dedupe {
+				names[value] = name
+			}
+		}
+		return style(name), nil
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	return s, args, nil
+}
+
+// RenderNamedMap is RenderNamed with the values keyed by name, e.g. for pgx.NamedArgs or
+// sqlx.Named.
+func (b Base) RenderNamedMap(e *expr.Expression, style NamedStyle) (s string, args map[string]any, err error) {
+	s, named, err := b.RenderNamed(e, style)
+	if err != nil {
+		return "", nil, err
+	}
+
+	args = map[string]any{}
+	for _, arg := range named {
+		args[arg.Name] = arg.Value
+	}
+	return s, args, nil
+}
@@ -0,0 +1,101 @@
+package driver
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+
+	"github.com/grindlemire/go-lucene/pkg/lucene/expr"
+)
+
+func TestRenderNamed(t *testing.T) {
+	type tc struct {
+		driver interface {
+			RenderNamed(*expr.Expression, NamedStyle) (string, []sql.NamedArg, error)
+		}
+		style    NamedStyle
+		input    *expr.Expression
+		wantStr  string
+		wantArgs []sql.NamedArg
+	}
+
+	tcs := map[string]tc{
+		"colon_postgres": {
+			driver:   NewPostgresDriver(),
+			style:    ColonNamed,
+			input:    expr.AND(expr.Eq("a", "foo"), expr.Eq("b", 5)),
+			wantStr:  `("a" = :p1) AND ("b" = :p2)`,
+			wantArgs: []sql.NamedArg{sql.Named("p1", "foo"), sql.Named("p2", 5)},
+		},
+		"at_sqlserver": {
+			driver:   NewSQLServerDriver(),
+			style:    AtNamed,
+			input:    expr.Eq("a", "foo"),
+			wantStr:  `[a] = @p1`,
+			wantArgs: []sql.NamedArg{sql.Named("p1", "foo")},
+		},
+		"dollar_sqlite": {
+			driver:   NewSQLiteDriver(),
+			style:    DollarNamed,
+			input:    expr.Eq("a", true),
+			wantStr:  `"a" = $p1`,
+			wantArgs: []sql.NamedArg{sql.Named("p1", 1)},
+		},
+		"identical_values_share_a_name": {
+			driver:   NewPostgresDriver(),
+			style:    ColonNamed,
+			input:    expr.OR(expr.Eq("a", "foo"), expr.AND(expr.Eq("b", "foo"), expr.Eq("c", "bar"))),
+			wantStr:  `("a" = :p1) OR (("b" = :p1) AND ("c" = :p2))`,
+			wantArgs: []sql.NamedArg{sql.Named("p1", "foo"), sql.Named("p2", "bar")},
+		},
+		"same_text_different_type": {
+			driver:   NewPostgresDriver(),
+			style:    ColonNamed,
+			input:    expr.OR(expr.Eq("a", "5"), expr.Eq("b", 5)),
+			wantStr:  `("a" = :p1) OR ("b" = :p2)`,
+			wantArgs: []sql.NamedArg{sql.Named("p1", "5"), sql.Named("p2", 5)},
+		},
+		"raw_column_with_question_mark": {
+			driver:   NewPostgresDriver(),
+			style:    ColonNamed,
+			input:    expr.AND(expr.Eq(expr.RawColumn(`(attrs ? 'color')`), true), expr.Eq("a", "foo")),
+			wantStr:  `((attrs ? 'color') = :p1) AND ("a" = :p2)`,
+			wantArgs: []sql.NamedArg{sql.Named("p1", true), sql.Named("p2", "foo")},
+		},
+		"range": {
+			driver:   NewMySQLDriver(),
+			style:    ColonNamed,
+			input:    expr.Rang("a", 1, 1, true),
+			wantStr:  "`a` >= :p1 AND `a` <= :p1",
+			wantArgs: []sql.NamedArg{sql.Named("p1", 1)},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			gotStr, gotArgs, err := tc.driver.RenderNamed(tc.input, tc.style)
+			if err != nil {
+				t.Fatalf("got an unexpected error when rendering: %v", err)
+			}
+			if gotStr != tc.wantStr {
+				t.Fatalf(errTemplate, "generated sql does not match", tc.wantStr, gotStr)
+			}
+			if !reflect.DeepEqual(gotArgs, tc.wantArgs) {
+				t.Fatalf("args don't match:\n    wanted %v\n    got    %v", tc.wantArgs, gotArgs)
+			}
+		})
+	}
+}
+
+func TestRenderNamedMap(t *testing.T) {
+	got, args, err := NewPostgresDriver().RenderNamedMap(expr.OR(expr.Eq("a", "foo"), expr.Eq("b", "foo")), AtNamed)
+	if err != nil {
+		t.Fatalf("got an unexpected error when rendering: %v", err)
+	}
+	if want := `("a" = @p1) OR ("b" = @p1)`; got != want {
+		t.Fatalf(errTemplate, "generated sql does not match", want, got)
+	}
+	if want := map[string]any{"p1": "foo"}; !reflect.DeepEqual(args, want) {
+		t.Fatalf("args don't match:\n    wanted %v\n    got    %v", want, args)
+	}
+}
@@ -0,0 +1,152 @@
+package driver
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/grindlemire/go-lucene/pkg/lucene/expr"
+)
+
+// OracleDriver transforms a parsed lucene expression to an Oracle SQL filter.
+//
+// Notable differences from the Postgres driver:
+//   - Identifiers quote with double quotes, but names that would be valid
+//     unquoted identifiers are uppercased first. Oracle folds unquoted names
+//     to uppercase, so a:1 matches a column created as `a` or `A`. Names
+//     that need quoting anyway (spaces, mixed punctuation) keep their case.
+//   - Wildcards render as LIKE ... ESCAPE '\'. Oracle string literals have
+//     no backslash escapes, so the escape character is portable.
+//   - Regex (/pattern/) renders as REGEXP_LIKE(col, pattern).
+//   - Standalone `field:*` renders as `"FIELD" IS NOT NULL`.
+//   - Parameter placeholders are positional binds :1, :2, ...
+//   - Oracle has no boolean column type before 23ai, so bools serialize as
+//     1/0 both as literals and as params.
+//   - Fuzzy terms use the built-in UTL_MATCH.EDIT_DISTANCE.
+type OracleDriver struct {
+	Base
+}
+
+// oraclePlaceholder writes Oracle's :N binds.
+var oraclePlaceholder = numbered(":%d")
+
+// NewOracleDriver creates a new driver that will output Oracle filter strings
+// from parsed lucene expressions.
+func NewOracleDriver() OracleDriver {
+	fns := map[expr.Operator]RenderFN{}
+	for op, sharedFN := range Shared {
+		fns[op] = sharedFN
+	}
+	return OracleDriver{
+		Base: Base{
+			RenderFNs: fns,
+			Dialect:   oracleDialect{},
+		},
+	}
+}
+
+// RenderParam will render the expression into a parameterized query using Oracle's :N
+// positional bind format.
+func (d OracleDriver) RenderParam(e *expr.Expression) (s string, params []any, err error) {
+	return d.renderParamWith(e, oraclePlaceholder)
+}
+
+// Finalize returns the SQL and arguments of the fragment with :N placeholders numbered
+// from start, e.g. for a filter placed after the statement's own :1 and :2.
+func (d OracleDriver) Finalize(f Fragment, start int) (string, []any, error) {
+	return finalize(f, start, oraclePlaceholder)
+}
+
+// RenderScoredParam is Base.RenderScoredParam with :N binds.
+func (d OracleDriver) RenderScoredParam(e *expr.Expression) (where string, score string, params []any, err error) {
+	return d.renderScoredParam(e, oraclePlaceholder)
+}
+
+// oracleDialect implements Dialect for Oracle.
+type oracleDialect struct{}
+
+func (oracleDialect) RenderLike(left, right string, isRegex bool) (string, error) {
+	if isRegex {
+		return fmt.Sprintf("REGEXP_LIKE(%s, %s)", left, right), nil
+	}
+	return fmt.Sprintf(`%s LIKE %s ESCAPE '\'`, left, right), nil
+}
+
+func (oracleDialect) RenderStandaloneWild(left string) (string, error) {
+	return fmt.Sprintf("%s IS NOT NULL", left), nil
+}
+
+func (oracleDialect) PrepareLikePattern(pattern string) (string, bool) {
+	return translateWildcard(pattern, "%", "_", escapeWith(`\`, `\%_`)), false
+}
+
+func (oracleDialect) RenderEqualsFold(left, right string) (string, error) {
+	return fmt.Sprintf("LOWER(%s) = LOWER(%s)", left, right), nil
+}
+
+func (d oracleDialect) PrepareLikePatternFold(pattern string) (string, bool) {
+	return d.PrepareLikePattern(pattern)
+}
+
+// RenderLikeFold uses REGEXP_LIKE's i match parameter for regexes.
+func (oracleDialect) RenderLikeFold(left, right string, isRegex bool) (string, error) {
+	if isRegex {
+		return fmt.Sprintf("REGEXP_LIKE(%s, %s, 'i')", left, right), nil
+	}
+	return fmt.Sprintf(`LOWER(%s) LIKE LOWER(%s) ESCAPE '\'`, left, right), nil
+}
+
+func (oracleDialect) EscapeStringLiteral(s string) string {
+	return fmt.Sprintf("'%s'", strings.ReplaceAll(s, "'", "''"))
+}
+
+func (oracleDialect) SerializeBool(b bool) string {
+	if b {
+		return "1"
+	}
+	return "0"
+}
+
+// BoolParam returns 1 or 0 to match NUMBER(1) flag columns.
+func (oracleDialect) BoolParam(b bool) any {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// oracleTimestampFormat is the ANSI TIMESTAMP literal format. Times are converted to
+// UTC first since the literal has no zone.
+const oracleTimestampFormat = "2006-01-02 15:04:05.999999999"
+
+func (oracleDialect) SerializeTimestamp(t time.Time) string {
+	return "TIMESTAMP '" + t.UTC().Format(oracleTimestampFormat) + "'"
+}
+
+func (oracleDialect) TimestampParam(t time.Time) any { return t }
+
+// RenderFuzzy uses UTL_MATCH.EDIT_DISTANCE, which ships with every Oracle
+// database.
+func (oracleDialect) RenderFuzzy(left, right string, distance int) (string, error) {
+	return Levenshtein("UTL_MATCH.EDIT_DISTANCE")(left, right, distance)
+}
+
+// oracleUnquotedIdentifier matches names Oracle would accept without quotes.
+var oracleUnquotedIdentifier = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_$#]*$`)
+
+func (oracleDialect) QuoteColumn(name string) (string, error) {
+	if len(name) == 0 {
+		return "", fmt.Errorf("column name is empty")
+	}
+	if strings.ContainsRune(name, 0) {
+		return "", fmt.Errorf("column name contains a null byte: %q", name)
+	}
+	if strings.ContainsRune(name, '"') {
+		return "", fmt.Errorf("column name contains a double quote: %q", name)
+	}
+	if oracleUnquotedIdentifier.MatchString(name) {
+		name = strings.ToUpper(name)
+	}
+	return `"` + name + `"`, nil
+}
@@ -0,0 +1,121 @@
+package driver
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/grindlemire/go-lucene/pkg/lucene/expr"
+)
+
+func TestOracleDriver(t *testing.T) {
+	type tc struct {
+		input *expr.Expression
+		want  string
+	}
+
+	tcs := map[string]tc{
+		"simple_equals": {
+			input: expr.Eq("a", 5),
+			want:  `"A" = 5`,
+		},
+		"string_equals": {
+			input: expr.Eq("name", "it's"),
+			want:  `"NAME" = 'it''s'`,
+		},
+		"quoted_name_keeps_case": {
+			input: expr.Eq("First Name", "bob"),
+			want:  `"First Name" = 'bob'`,
+		},
+		"simple_and": {
+			input: expr.AND(expr.Eq("a", 5), expr.Eq("b", "foo")),
+			want:  `("A" = 5) AND ("B" = 'foo')`,
+		},
+		"bool_literal": {
+			input: expr.Eq("active", true),
+			want:  `"ACTIVE" = 1`,
+		},
+		"like_wildcard": {
+			input: expr.LIKE("a", "b*"),
+			want:  `"A" LIKE 'b%' ESCAPE '\'`,
+		},
+		"like_escapes_metacharacters": {
+			input: expr.LIKE("a", `100%_?*`),
+			want:  `"A" LIKE '100\%\__%' ESCAPE '\'`,
+		},
+		"standalone_wild": {
+			input: expr.LIKE("a", "*"),
+			want:  `"A" IS NOT NULL`,
+		},
+		"regexp": {
+			input: expr.LIKE("a", expr.REGEXP("/^b.*/")),
+			want:  `REGEXP_LIKE("A", '^b.*')`,
+		},
+		"timestamp_range": {
+			input: expr.Rang("t", time.Date(2024, 1, 1, 10, 30, 0, 0, time.UTC), expr.WILD("*"), true),
+			want:  `"T" >= TIMESTAMP '2024-01-01 10:30:00'`,
+		},
+		"fuzzy_edit_distance": {
+			input: expr.FUZZY(expr.Eq("a", "foo"), 2),
+			want:  `UTL_MATCH.EDIT_DISTANCE("A", 'foo') <= 2`,
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			got, err := NewOracleDriver().Render(tc.input)
+			if err != nil {
+				t.Fatalf("got an unexpected error when rendering: %v", err)
+			}
+			if tc.want != got {
+				t.Fatalf(errTemplate, "generated sql does not match", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestOracleDriverParam(t *testing.T) {
+	type tc struct {
+		input      *expr.Expression
+		wantStr    string
+		wantParams []any
+	}
+
+	tcs := map[string]tc{
+		"positional_binds": {
+			input:      expr.AND(expr.Eq("a", "foo"), expr.IN("b", expr.LIST(expr.Lit("x"), expr.Lit("y")))),
+			wantStr:    `("A" = :1) AND ("B" IN (:2, :3))`,
+			wantParams: []any{"foo", "x", "y"},
+		},
+		"bool_param": {
+			input:      expr.Eq("active", false),
+			wantStr:    `"ACTIVE" = :1`,
+			wantParams: []any{0},
+		},
+		"like_param": {
+			input:      expr.LIKE("a", "b_*"),
+			wantStr:    `"A" LIKE :1 ESCAPE '\'`,
+			wantParams: []any{`b\_%`},
+		},
+		"regexp_param": {
+			input:      expr.LIKE("a", expr.REGEXP("/b+/")),
+			wantStr:    `REGEXP_LIKE("A", :1)`,
+			wantParams: []any{"b+"},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			gotStr, gotParams, err := NewOracleDriver().RenderParam(tc.input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if gotStr != tc.wantStr {
+				t.Fatalf(errTemplate, "generated sql does not match", tc.wantStr, gotStr)
+			}
+			if !reflect.DeepEqual(gotParams, tc.wantParams) {
+				t.Fatalf("params don't match:\n    wanted %v\n    got    %v", tc.wantParams, gotParams)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package driver
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/grindlemire/go-lucene/pkg/lucene/expr"
+)
+
+// placeholder marks where a param goes in the sql rendered by renderParam until the driver writes
+// its own placeholders, so a ? that is part of the sql, e.g. in a mapped raw column, is never
+// taken for one. It can't come from a query since the params hold the values a query compares
+// against, and it never reaches a RenderFN or dialect, which see ? as they always have.
+const placeholder = "\x00?\x00"
+
+// rawQuestionMark stands in for a ? that is part of the sql while sql holding placeholders is
+// passed to a RenderFN or a dialect, so the only ? they see are the placeholders.
+const rawQuestionMark = "\x00\x01\x00"
+
+var (
+	toQuestionMarks   = strings.NewReplacer(placeholder, "?", "?", rawQuestionMark)
+	fromQuestionMarks = strings.NewReplacer("?", placeholder, rawQuestionMark, "?")
+)
+
+// withQuestionMarks calls render with the placeholder markers in args written as ?, the way
+// RenderFNs and dialects have always seen params, and marks the ? in what it returns as
+// placeholders again. The marker never leaves Base this way, and a ? that is part of the sql
+// is never taken for a placeholder. args without placeholders are passed as they are.
+func withQuestionMarks(render func(args []string) (string, error), args ...string) (string, error) {
+	if !slices.ContainsFunc(args, func(arg string) bool { return strings.Contains(arg, placeholder) }) {
+		return render(args)
+	}
+	marked := make([]string, len(args))
+	for i, arg := range args {
+		marked[i] = toQuestionMarks.Replace(arg)
+	}
+	s, err := render(marked)
+	if err != nil {
+		return "", err
+	}
+	return fromQuestionMarks.Replace(s), nil
+}
+
+// callFN calls fn through withQuestionMarks.
+func callFN(fn RenderFN, left, right string) (string, error) {
+	return withQuestionMarks(func(args []string) (string, error) { return fn(args[0], args[1]) }, left, right)
+}
+
+// placeholderFormat writes the placeholder of a driver for the param at the given 1-based index.
+type placeholderFormat func(index int, param any) (string, error)
+
+// questionMarks keeps the ? placeholders of MySQL and SQLite.
+func questionMarks(int, any) (string, error) {
+	return "?", nil
+}
+
+// numbered writes placeholders numbered with format, e.g. $%d for $1, $2, ...
+func numbered(format string) placeholderFormat {
+	return func(index int, _ any) (string, error) {
+		return fmt.Sprintf(format, index), nil
+	}
+}
+
+// writePlaceholders replaces the placeholder markers in str with the placeholders written by
+// format, counting from start, and returns the index after the last one.
+func writePlaceholders(str string, params []any, start int, format placeholderFormat) (string, int, error) {
+	if n := strings.Count(str, placeholder); n != len(params) {
+		return "", 0, fmt.Errorf("rendered %d placeholders for %d params", n, len(params))
+	}
+
+	result := strings.Builder{}
+	index := start
+	for _, param := range params {
+		before, after, _ := strings.Cut(str, placeholder)
+		p, err := format(index, param)
+		if err != nil {
+			return "", 0, err
+		}
+		result.WriteString(before)
+		result.WriteString(p)
+		str = after
+		index++
+	}
+	result.WriteString(str)
+	return result.String(), index, nil
+}
+
+// renderParamWith is RenderParam with the placeholders written by format.
+func (b Base) renderParamWith(e *expr.Expression, format placeholderFormat) (string, []any, error) {
+	e, err := b.resolveBareTerms(e)
+	if err != nil {
+		return "", nil, err
+	}
+	s, params, err := b.renderParam(e)
+	if err != nil {
+		return "", nil, err
+	}
+	s, _, err = writePlaceholders(s, params, 1, format)
+	if err != nil {
+		return "", nil, err
+	}
+	return s, params, nil
+}
+
+// finalize is Finalize with the placeholders written by format.
+func finalize(f Fragment, start int, format placeholderFormat) (string, []any, error) {
+	if err := checkFragment(f, start); err != nil {
+		return "", nil, err
+	}
+	s, _, err := writePlaceholders(markFragment(f.SQL), f.Args, start, format)
+	if err != nil {
+		return "", nil, err
+	}
+	return s, f.Args, nil
+}
@@ -0,0 +1,39 @@
+package driver
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/grindlemire/go-lucene/pkg/lucene/expr"
+)
+
+func TestRenderFNsSeeQuestionMarks(t *testing.T) {
+	fns := map[expr.Operator]RenderFN{}
+	for op, fn := range Shared {
+		fns[op] = fn
+	}
+	seen := []string{}
+	fns[expr.Equals] = func(left, right string) (string, error) {
+		seen = append(seen, right)
+		if right == "?" {
+			return fmt.Sprintf("%s = CAST(? AS TEXT)", left), nil
+		}
+		return fmt.Sprintf("%s = %s", left, right), nil
+	}
+	d := PostgresDriver{Base: Base{RenderFNs: fns, Dialect: postgresDialect{}}}
+
+	got, params, err := d.RenderParam(expr.AND(expr.Eq(expr.RawColumn(`(attrs ? 'color')`), "red"), expr.Eq("a", "foo")))
+	if err != nil {
+		t.Fatalf("got an unexpected error when rendering: %v", err)
+	}
+	if want := `((attrs ? 'color') = CAST($1 AS TEXT)) AND ("a" = CAST($2 AS TEXT))`; got != want {
+		t.Fatalf(errTemplate, "generated sql doesn't match", want, got)
+	}
+	if want := []any{"red", "foo"}; !reflect.DeepEqual(params, want) {
+		t.Fatalf("params don't match:\n    wanted %v\n    got    %v", want, params)
+	}
+	if want := []string{"?", "?"}; !reflect.DeepEqual(seen, want) {
+		t.Fatalf("render fn saw the wrong values:\n    wanted %q\n    got    %q", want, seen)
+	}
+}
@@ -3,6 +3,7 @@ package driver
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/grindlemire/go-lucene/pkg/lucene/expr"
 )
@@ -12,6 +13,9 @@ type PostgresDriver struct {
 	Base
 }
 
+// postgresPlaceholder writes PostgreSQL's $N placeholders.
+var postgresPlaceholder = numbered("$%d")
+
 // NewPostgresDriver creates a new driver that will output postgres filter strings from parsed lucene expressions.
 func NewPostgresDriver() PostgresDriver {
 	fns := map[expr.Operator]RenderFN{}
@@ -30,27 +34,18 @@ func NewPostgresDriver() PostgresDriver {
 // The returned string will contain $1, $2, $3, etc. placeholders and the params will contain the values
 // that should be passed to the query.
 func (p PostgresDriver) RenderParam(e *expr.Expression) (s string, params []any, err error) {
-	// First, use the base implementation to get the result with ? placeholders
-	str, params, err := p.Base.RenderParam(e)
-	if err != nil {
-		return s, params, err
-	}
-
-	// Then convert ? placeholders to $N format
-	paramIndex := 1
-	result := strings.Builder{}
-	i := 0
-	for i < len(str) {
-		if str[i] == '?' {
-			result.WriteString(fmt.Sprintf("$%d", paramIndex))
-			paramIndex++
-		} else {
-			result.WriteByte(str[i])
-		}
-		i++
-	}
+	return p.renderParamWith(e, postgresPlaceholder)
+}
 
-	return result.String(), params, nil
+// Finalize returns the SQL and arguments of the fragment with $N placeholders numbered
+// from start, e.g. for a filter placed after the statement's own $1 and $2.
+func (p PostgresDriver) Finalize(f Fragment, start int) (string, []any, error) {
+	return finalize(f, start, postgresPlaceholder)
+}
+
+// RenderScoredParam is Base.RenderScoredParam with $N placeholders.
+func (p PostgresDriver) RenderScoredParam(e *expr.Expression) (where string, score string, params []any, err error) {
+	return p.renderScoredParam(e, postgresPlaceholder)
 }
 
 // postgresDialect implements Dialect for PostgreSQL. It is a lift-and-shift
@@ -70,12 +65,31 @@ func (postgresDialect) RenderStandaloneWild(left string) (string, error) {
 	return fmt.Sprintf("%s SIMILAR TO '%%'", left), nil
 }
 
+// PrepareLikePattern translates the pattern for SIMILAR TO, where an escaped * or ? has to be
+// escaped again since both are also SIMILAR TO metacharacters.
 func (postgresDialect) PrepareLikePattern(pattern string) (string, bool) {
-	pattern = strings.ReplaceAll(pattern, "%", `\%`)
-	pattern = strings.ReplaceAll(pattern, "_", `\_`)
-	pattern = strings.ReplaceAll(pattern, "*", "%")
-	pattern = strings.ReplaceAll(pattern, "?", "_")
-	return pattern, false
+	return translateWildcard(pattern, "%", "_", escapeWith(`\`, `\%_*?`)), false
+}
+
+func (postgresDialect) RenderEqualsFold(left, right string) (string, error) {
+	return fmt.Sprintf("LOWER(%s) = LOWER(%s)", left, right), nil
+}
+
+// PrepareLikePatternFold prepares the pattern for ILIKE, which takes the same
+// escapes as SIMILAR TO but none of its alternation or grouping, so patterns
+// that use those are translated to an anchored regex for ~* instead.
+func (d postgresDialect) PrepareLikePatternFold(pattern string) (string, bool) {
+	if strings.ContainsAny(pattern, similarToOnlyMetachars) {
+		return luceneWildcardToRegex(pattern), true
+	}
+	return d.PrepareLikePattern(pattern)
+}
+
+func (postgresDialect) RenderLikeFold(left, right string, isRegex bool) (string, error) {
+	if isRegex {
+		return fmt.Sprintf("%s ~* %s", left, right), nil
+	}
+	return fmt.Sprintf("%s ILIKE %s", left, right), nil
 }
 
 func (postgresDialect) EscapeStringLiteral(s string) string {
@@ -91,6 +105,185 @@ func (postgresDialect) SerializeBool(b bool) string {
 
 func (postgresDialect) BoolParam(b bool) any { return b }
 
+// SerializeTimestamp renders a timestamptz literal so comparisons against both
+// timestamp and timestamptz columns keep the time zone of the value.
+func (postgresDialect) SerializeTimestamp(t time.Time) string {
+	return fmt.Sprintf("'%s'::timestamptz", t.Format(time.RFC3339Nano))
+}
+
+func (postgresDialect) TimestampParam(t time.Time) any { return t }
+
+// RenderFuzzy uses levenshtein from the fuzzystrmatch extension. Set
+// Base.Fuzzy to Similarity() to use pg_trgm instead.
+func (postgresDialect) RenderFuzzy(left, right string, distance int) (string, error) {
+	return Levenshtein("levenshtein")(left, right, distance)
+}
+
+// FullTextQuery writes a lone term or phrase for websearch_to_tsquery and
+// anything else in to_tsquery syntax, which also has prefixes, distances and
+// grouping.
+func (postgresDialect) FullTextQuery(q *FullTextQuery) (string, error) {
+	if !isWebSearch(q) {
+		err := walkFullText(q, func(c *FullTextQuery) error {
+			if c.Op == FullTextNear && tsNearAlternatives(len(strings.Fields(c.Text)), c.Slop) > maxTsNearAlternatives {
+				return fmt.Errorf("unable to render full-text search: the slop of %q is too large for a tsquery", c.Text)
+			}
+			return nil
+		})
+		return tsquery(q), err
+	}
+	if q.Op == FullTextTerm && !strings.ContainsAny(q.Text, `"-`) && !strings.EqualFold(q.Text, "or") {
+		return q.Text, nil
+	}
+	return `"` + strings.ReplaceAll(q.Text, `"`, " ") + `"`, nil
+}
+
+// RenderFullText searches the column's to_tsvector with the query, both in the
+// connection's default_text_search_config.
+func (postgresDialect) RenderFullText(left, right string, q *FullTextQuery) (string, error) {
+	fn := "to_tsquery"
+	if isWebSearch(q) {
+		fn = "websearch_to_tsquery"
+	}
+	return fmt.Sprintf("to_tsvector(%s) @@ %s(%s)", left, fn, right), nil
+}
+
+// isWebSearch reports whether the query is simple enough for websearch_to_tsquery.
+func isWebSearch(q *FullTextQuery) bool {
+	return q.Op == FullTextTerm || q.Op == FullTextPhrase
+}
+
+// tsquery writes the query in to_tsquery syntax, quoting every word so
+// punctuation in it isn't read as an operator.
+func tsquery(q *FullTextQuery) string {
+	switch q.Op {
+	case FullTextTerm:
+		return tsLexeme(q.Text)
+	case FullTextPhrase:
+		words := []string{}
+		for _, w := range strings.Fields(q.Text) {
+			words = append(words, tsLexeme(w))
+		}
+		return strings.Join(words, " <-> ")
+	case FullTextPrefix:
+		words := []string{}
+		for _, w := range strings.Fields(q.Text) {
+			words = append(words, tsLexeme(w))
+		}
+		return strings.Join(words, " <-> ") + ":*"
+	case FullTextNear:
+		return tsNear(strings.Fields(q.Text), q.Slop)
+	case FullTextAnd:
+		return joinFullText(q.Clauses, " & ", tsGrouped)
+	case FullTextOr:
+		return joinFullText(q.Clauses, " | ", tsGrouped)
+	}
+
+	// ! binds tighter than <->, so a negated phrase is grouped too
+	inner := q.Clauses[0]
+	if inner.isCompound() || len(strings.Fields(inner.Text)) > 1 {
+		return "!(" + tsquery(inner) + ")"
+	}
+	return "!" + tsquery(inner)
+}
+
+// tsGrouped is tsquery for a clause of an & or |, grouping a proximity match
+// since it is written as an | of its distances.
+func tsGrouped(q *FullTextQuery) string {
+	if q.Op == FullTextNear {
+		return "(" + tsquery(q) + ")"
+	}
+	return tsquery(q)
+}
+
+// maxTsNearAlternatives bounds how many distances a proximity match can
+// expand to.
+const maxTsNearAlternatives = 100
+
+// tsNear writes a proximity match as an | of every in order distance between
+// the words whose extra positions add up to at most slop, since <N> only
+// matches an exact distance. e.g. "quick fox"~2 is
+// 'quick' <-> 'fox' | 'quick' <2> 'fox' | 'quick' <3> 'fox'.
+func tsNear(words []string, slop int) string {
+	alternatives := []string{}
+	var next func(s string, i int, slop int)
+	next = func(s string, i int, slop int) {
+		if i == len(words) {
+			alternatives = append(alternatives, s)
+			return
+		}
+		for extra := 0; extra <= slop; extra++ {
+			op := "<->"
+			if extra > 0 {
+				op = fmt.Sprintf("<%d>", extra+1)
+			}
+			next(s+" "+op+" "+tsLexeme(words[i]), i+1, slop-extra)
+		}
+	}
+	next(tsLexeme(words[0]), 1, slop)
+	return strings.Join(alternatives, " | ")
+}
+
+// tsNearAlternatives counts the distances tsNear writes for the words, which
+// is the number of ways to spread up to slop extra positions over the gaps
+// between them.
+func tsNearAlternatives(words, slop int) int {
+	count := 1
+	for i := 1; i < words; i++ {
+		count = count * (slop + i) / i
+		if count > maxTsNearAlternatives {
+			break
+		}
+	}
+	return count
+}
+
+func tsLexeme(word string) string {
+	word = strings.ReplaceAll(word, `\`, `\\`)
+	return "'" + strings.ReplaceAll(word, "'", "''") + "'"
+}
+
+// RenderJSONPath walks the path with -> and reads the last key as text with
+// ->>, casting it to numeric for numbers. Unlike RenderJSONContains it works
+// on json as well as jsonb columns.
+func (d postgresDialect) RenderJSONPath(left string, path []string, numeric bool) (string, error) {
+	s := left
+	for i, key := range path {
+		op := "->"
+		if i == len(path)-1 {
+			op = "->>"
+		}
+		s += op + d.EscapeStringLiteral(key)
+	}
+	if numeric {
+		return fmt.Sprintf("(%s)::numeric", s), nil
+	}
+	return s, nil
+}
+
+// RenderJSONContains uses jsonb containment, which a GIN index on the column
+// can serve. The column must be jsonb.
+func (postgresDialect) RenderJSONContains(left, right string) (string, error) {
+	return fmt.Sprintf("%s @> %s", left, right), nil
+}
+
+// RenderArrayContains uses = ANY for a single value and the && overlap
+// operator, which a GIN index on the column can serve, for a list.
+func (postgresDialect) RenderArrayContains(left string, values []string) (string, error) {
+	if len(values) == 1 {
+		return fmt.Sprintf("%s = ANY(%s)", values[0], left), nil
+	}
+	return fmt.Sprintf("%s && ARRAY[%s]", left, strings.Join(values, ", ")), nil
+}
+
+func (postgresDialect) RenderArrayLength(left string) (string, error) {
+	return fmt.Sprintf("cardinality(%s)", left), nil
+}
+
+func (postgresDialect) ArrayElements(left string) (source, element string) {
+	return fmt.Sprintf("unnest(%s) AS elem", left), "elem"
+}
+
 func (postgresDialect) QuoteColumn(name string) (string, error) {
 	if strings.ContainsRune(name, '"') {
 		return "", fmt.Errorf("column name contains a double quote: %q", name)
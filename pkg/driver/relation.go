@@ -0,0 +1,264 @@
+package driver
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/grindlemire/go-lucene/pkg/lucene/expr"
+)
+
+// Relation is a one-to-many child table of the table being queried, e.g. the comments of a post.
+// Fields prefixed with its name, e.g. comments.author, match the parent rows that have a child row
+// matching them.
+type Relation struct {
+	// Name is the prefix of the relation's fields, e.g. comments for comments.author.
+	Name string
+	// Table is the child table, Name when empty.
+	Table string
+	// ForeignKey is the column of the child table holding the key of its parent, e.g. post_id.
+	ForeignKey string
+	// ParentTable and ParentKey are the table being queried and the column the foreign key refers
+	// to, e.g. posts and id.
+	ParentTable string
+	ParentKey   string
+}
+
+func (r Relation) table() string {
+	if r.Table == "" {
+		return r.Name
+	}
+	return r.Table
+}
+
+// relationField splits a field of one of the relations into the relation and the column of the
+// child table, e.g. comments.author into comments and author.
+func (b Base) relationField(name string) (*Relation, string, bool) {
+	for i, r := range b.Relations {
+		if column, found := strings.CutPrefix(name, r.Name+"."); found && r.Name != "" {
+			return &b.Relations[i], column, true
+		}
+	}
+	return nil, "", false
+}
+
+// quoteColumn quotes a column, qualifying the fields of a relation with the child table so they
+// can't be mistaken for a column of the parent inside its subquery.
+func (b Base) quoteColumn(name string) (string, error) {
+	if r, column, ok := b.relationField(name); ok {
+		return b.quoteQualified(r.table(), column)
+	}
+	return b.dialect().QuoteColumn(name)
+}
+
+// quoteTable quotes each part of a dotted table name, e.g. public.comments.
+func (b Base) quoteTable(table string) (string, error) {
+	parts := []string{}
+	for _, name := range strings.Split(table, ".") {
+		quoted, err := b.dialect().QuoteColumn(name)
+		if err != nil {
+			return "", err
+		}
+		parts = append(parts, quoted)
+	}
+	return strings.Join(parts, "."), nil
+}
+
+// quoteQualified quotes a column of the table.
+func (b Base) quoteQualified(table string, column string) (string, error) {
+	quoted, err := b.quoteTable(table)
+	if err != nil {
+		return "", err
+	}
+	c, err := b.dialect().QuoteColumn(column)
+	if err != nil {
+		return "", err
+	}
+	return quoted + "." + c, nil
+}
+
+// relationOf returns the relation every field of the expression belongs to, or nil when it has
+// fields outside of a relation, fields of several or a negation, which is rendered outside of the
+// subquery so -comments.author:bob matches posts without a comment by bob.
+func (b Base) relationOf(e *expr.Expression) *Relation {
+	relations, unrelated, negated := b.relationsIn(e)
+	if len(relations) != 1 || unrelated || negated {
+		return nil
+	}
+	return relations[0]
+}
+
+// relationsIn returns the relations of the fields in the expression, whether it has fields
+// outside of them and whether it has a negation.
+func (b Base) relationsIn(e *expr.Expression) (relations []*Relation, unrelated bool, negated bool) {
+	if b.within != nil || len(b.Relations) == 0 {
+		return nil, true, false
+	}
+	var walk func(in any)
+	walk = func(in any) {
+		switch v := in.(type) {
+		case *expr.Expression:
+			if v == nil {
+				return
+			}
+			switch v.Op {
+			case expr.Not, expr.MustNot:
+				negated = true
+			case expr.Literal:
+				if name := fieldName(v); name != "" {
+					r, _, ok := b.relationField(name)
+					if !ok {
+						unrelated = true
+					} else if !slices.Contains(relations, r) {
+						relations = append(relations, r)
+					}
+				}
+				return
+			}
+			walk(v.Left)
+			walk(v.Right)
+		case []*expr.Expression:
+			for _, item := range v {
+				walk(item)
+			}
+		case *expr.RangeBoundary:
+			walk(v.Min)
+			walk(v.Max)
+		}
+	}
+	walk(e)
+	return relations, unrelated, negated
+}
+
+// relationMatch reports whether the expression is rendered as subqueries of its relations: an
+// and/or with a field of one, a clause of a single relation or the negation of one.
+func (b Base) relationMatch(e *expr.Expression) bool {
+	switch e.Op {
+	case expr.And, expr.Or:
+		relations, _, _ := b.relationsIn(e)
+		return len(relations) > 0
+	case expr.Not, expr.MustNot:
+		inner, _ := e.Left.(*expr.Expression)
+		return inner != nil && b.relationOf(inner) != nil
+	}
+	return b.relationOf(e) != nil
+}
+
+func (b Base) renderRelation(e *expr.Expression) (string, error) {
+	s, _, err := b.renderRelationMatch(e, false)
+	return s, err
+}
+
+func (b Base) renderRelationParam(e *expr.Expression) (string, []any, error) {
+	return b.renderRelationMatch(e, true)
+}
+
+// renderRelationMatch renders the clauses of an and/or on the same relation as a single subquery,
+// so comments.author:alice AND comments.approved:true needs both to hold for the same comment.
+// Other clauses, including negated ones, are rendered as they would be on their own.
+func (b Base) renderRelationMatch(e *expr.Expression, param bool) (string, []any, error) {
+	switch e.Op {
+	case expr.Not, expr.MustNot:
+		inner, params, err := b.renderExists(b.relationOf(e.Left.(*expr.Expression)), e.Left.(*expr.Expression), param)
+		if err != nil {
+			return "", nil, err
+		}
+		fn, ok := b.RenderFNs[e.Op]
+		if !ok {
+			return "", nil, fmt.Errorf("unable to render operator [%s]", e.Op)
+		}
+		s, err := callFN(fn, inner, "")
+		return s, params, err
+	case expr.And, expr.Or:
+	default:
+		return b.renderExists(b.relationOf(e), e, param)
+	}
+
+	// group the clauses of the chain by relation, in the place of the first clause of each
+	type clause struct {
+		relation *Relation
+		e        *expr.Expression
+	}
+	clauses := []*clause{}
+	grouped := map[*Relation]*clause{}
+	for _, operand := range flatten(e, e.Op) {
+		r := b.relationOf(operand)
+		if r == nil {
+			clauses = append(clauses, &clause{e: operand})
+			continue
+		}
+		if c, ok := grouped[r]; ok {
+			c.e = &expr.Expression{Op: e.Op, Left: c.e, Right: operand}
+			continue
+		}
+		grouped[r] = &clause{relation: r, e: operand}
+		clauses = append(clauses, grouped[r])
+	}
+
+	fn, ok := b.RenderFNs[e.Op]
+	if !ok {
+		return "", nil, fmt.Errorf("unable to render operator [%s]", e.Op)
+	}
+	s := ""
+	params := []any{}
+	for i, c := range clauses {
+		var str string
+		var cparams []any
+		var err error
+		if c.relation != nil {
+			str, cparams, err = b.renderExists(c.relation, c.e, param)
+		} else {
+			str, cparams, err = b.renderAny(c.e, param)
+		}
+		if err != nil {
+			return "", nil, err
+		}
+		params = append(params, cparams...)
+		if len(clauses) > 1 && (c.relation != nil || !b.isSimple(c.e)) {
+			str = "(" + str + ")"
+		}
+		switch i {
+		case 0:
+			s = str
+		case 1:
+			s, err = callFN(fn, s, str)
+		default:
+			s, err = callFN(fn, "("+s+")", str)
+		}
+		if err != nil {
+			return "", nil, err
+		}
+	}
+	return s, params, nil
+}
+
+// renderExists renders the clause on the fields of a relation as a subquery matching the child
+// rows of the parent row.
+func (b Base) renderExists(r *Relation, e *expr.Expression, param bool) (string, []any, error) {
+	if r.ForeignKey == "" || r.ParentTable == "" || r.ParentKey == "" {
+		return "", nil, fmt.Errorf("unable to render relation %s: it needs a foreign key, parent table and parent key", r.Name)
+	}
+	table, err := b.quoteTable(r.table())
+	if err != nil {
+		return "", nil, err
+	}
+	foreignKey, err := b.quoteQualified(r.table(), r.ForeignKey)
+	if err != nil {
+		return "", nil, err
+	}
+	parentKey, err := b.quoteQualified(r.ParentTable, r.ParentKey)
+	if err != nil {
+		return "", nil, err
+	}
+
+	rb := b
+	rb.within = r
+	cond, params, err := rb.renderAny(e, param)
+	if err != nil {
+		return "", nil, err
+	}
+	if e.Op == expr.And || e.Op == expr.Or {
+		cond = "(" + cond + ")"
+	}
+	return fmt.Sprintf("EXISTS (SELECT 1 FROM %s WHERE %s = %s AND %s)", table, foreignKey, parentKey, cond), params, nil
+}
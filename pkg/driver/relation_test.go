@@ -0,0 +1,151 @@
+package driver
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/grindlemire/go-lucene/pkg/lucene/expr"
+)
+
+func TestRelations(t *testing.T) {
+	relations := []Relation{
+		{Name: "comments", ForeignKey: "post_id", ParentTable: "posts", ParentKey: "id"},
+		{Name: "tags", Table: "post_tags", ForeignKey: "post_id", ParentTable: "posts", ParentKey: "id"},
+	}
+	postgres := NewPostgresDriver()
+	postgres.Relations = relations
+	mysql := NewMySQLDriver()
+	mysql.Relations = relations
+	sqlite := NewSQLiteDriver()
+	sqlite.Relations = relations
+	oracle := NewOracleDriver()
+	oracle.Relations = relations
+	sqlserver := NewSQLServerDriver()
+	sqlserver.Relations = relations
+	fullText := NewPostgresDriver()
+	fullText.Relations = relations
+	fullText.FullTextFields = []string{"comments.body"}
+	incomplete := NewPostgresDriver()
+	incomplete.Relations = []Relation{{Name: "comments", ForeignKey: "post_id"}}
+
+	type tc struct {
+		driver interface {
+			Render(*expr.Expression) (string, error)
+		}
+		input *expr.Expression
+		want  string
+		err   string
+	}
+
+	tcs := map[string]tc{
+		"postgres_field": {
+			driver: postgres,
+			input:  expr.AND(expr.Eq("comments.author", "alice"), expr.Eq("status", "open")),
+			want:   `(EXISTS (SELECT 1 FROM "comments" WHERE "comments"."post_id" = "posts"."id" AND "comments"."author" = 'alice')) AND ("status" = 'open')`,
+		},
+		"postgres_grouped": {
+			driver: postgres,
+			input:  expr.AND(expr.AND(expr.Eq("status", "open"), expr.Eq("comments.author", "alice")), expr.Rang("comments.score", 1, 5, true)),
+			want:   `("status" = 'open') AND (EXISTS (SELECT 1 FROM "comments" WHERE "comments"."post_id" = "posts"."id" AND (("comments"."author" = 'alice') AND ("comments"."score" >= 1 AND "comments"."score" <= 5))))`,
+		},
+		"postgres_only_relation": {
+			driver: postgres,
+			input:  expr.OR(expr.Eq("comments.author", "alice"), expr.LIKE("comments.author", "bo*")),
+			want:   `EXISTS (SELECT 1 FROM "comments" WHERE "comments"."post_id" = "posts"."id" AND (("comments"."author" = 'alice') OR ("comments"."author" SIMILAR TO 'bo%')))`,
+		},
+		"postgres_not": {
+			driver: postgres,
+			input:  expr.NOT(expr.Eq("comments.author", "bob")),
+			want:   `NOT(EXISTS (SELECT 1 FROM "comments" WHERE "comments"."post_id" = "posts"."id" AND "comments"."author" = 'bob'))`,
+		},
+		"postgres_must_not_beside_match": {
+			driver: postgres,
+			input:  expr.AND(expr.MUST(expr.Eq("comments.author", "alice")), expr.MUSTNOT(expr.Eq("comments.spam", true))),
+			want:   `(EXISTS (SELECT 1 FROM "comments" WHERE "comments"."post_id" = "posts"."id" AND "comments"."author" = 'alice')) AND (NOT(EXISTS (SELECT 1 FROM "comments" WHERE "comments"."post_id" = "posts"."id" AND "comments"."spam" = true)))`,
+		},
+		"postgres_not_null": {
+			driver: postgres,
+			input:  expr.NOT(expr.Eq("comments.author", expr.NULL())),
+			want:   `NOT(EXISTS (SELECT 1 FROM "comments" WHERE "comments"."post_id" = "posts"."id" AND "comments"."author" IS NULL))`,
+		},
+		"postgres_two_relations": {
+			driver: postgres,
+			input:  expr.OR(expr.Eq("comments.author", "alice"), expr.Eq("tags.name", "go")),
+			want:   `(EXISTS (SELECT 1 FROM "comments" WHERE "comments"."post_id" = "posts"."id" AND "comments"."author" = 'alice')) OR (EXISTS (SELECT 1 FROM "post_tags" WHERE "post_tags"."post_id" = "posts"."id" AND "post_tags"."name" = 'go'))`,
+		},
+		"postgres_full_text_field": {
+			driver: fullText,
+			input:  expr.Eq("comments.body", "quick"),
+			want:   `EXISTS (SELECT 1 FROM "comments" WHERE "comments"."post_id" = "posts"."id" AND to_tsvector("comments"."body") @@ websearch_to_tsquery('quick'))`,
+		},
+		"postgres_other_dotted_field_unchanged": {
+			driver: postgres,
+			input:  expr.Eq("commentsx.author", "alice"),
+			want:   `"commentsx.author" = 'alice'`,
+		},
+		"mysql_field": {
+			driver: mysql,
+			input:  expr.AND(expr.Eq("comments.author", "alice"), expr.Eq("status", "open")),
+			want:   "(EXISTS (SELECT 1 FROM `comments` WHERE `comments`.`post_id` = `posts`.`id` AND `comments`.`author` = 'alice')) AND (`status` = 'open')",
+		},
+		"sqlite_not": {
+			driver: sqlite,
+			input:  expr.MUSTNOT(expr.Eq("comments.author", "bob")),
+			want:   `NOT(EXISTS (SELECT 1 FROM "comments" WHERE "comments"."post_id" = "posts"."id" AND "comments"."author" = 'bob'))`,
+		},
+		"oracle_field": {
+			driver: oracle,
+			input:  expr.Eq("comments.author", "alice"),
+			want:   `EXISTS (SELECT 1 FROM "COMMENTS" WHERE "COMMENTS"."POST_ID" = "POSTS"."ID" AND "COMMENTS"."AUTHOR" = 'alice')`,
+		},
+		"sqlserver_field": {
+			driver: sqlserver,
+			input:  expr.Eq("tags.name", "go"),
+			want:   `EXISTS (SELECT 1 FROM [post_tags] WHERE [post_tags].[post_id] = [posts].[id] AND [post_tags].[name] = N'go')`,
+		},
+		"incomplete_relation_errors": {
+			driver: incomplete,
+			input:  expr.Eq("comments.author", "alice"),
+			err:    "unable to render relation comments",
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			got, err := tc.driver.Render(tc.input)
+			if tc.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Fatalf(errTemplate, "error doesn't match", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("got an unexpected error when rendering: %v", err)
+			}
+			if got != tc.want {
+				t.Fatalf(errTemplate, "generated sql doesn't match", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestRelationsParam(t *testing.T) {
+	d := NewPostgresDriver()
+	d.Relations = []Relation{{Name: "comments", ForeignKey: "post_id", ParentTable: "posts", ParentKey: "id"}}
+
+	got, params, err := d.RenderParam(expr.AND(
+		expr.AND(expr.Eq("status", "open"), expr.Eq("comments.author", "alice")),
+		expr.MUSTNOT(expr.Eq("comments.author", "bob")),
+	))
+	if err != nil {
+		t.Fatalf("got an unexpected error when rendering: %v", err)
+	}
+	want := `(("status" = $1) AND (EXISTS (SELECT 1 FROM "comments" WHERE "comments"."post_id" = "posts"."id" AND "comments"."author" = $2))) AND (NOT(EXISTS (SELECT 1 FROM "comments" WHERE "comments"."post_id" = "posts"."id" AND "comments"."author" = $3)))`
+	if got != want {
+		t.Fatalf(errTemplate, "generated sql doesn't match", want, got)
+	}
+	if want := []any{"open", "alice", "bob"}; !reflect.DeepEqual(params, want) {
+		t.Fatalf("params don't match:\n    wanted %v\n    got    %v", want, params)
+	}
+}
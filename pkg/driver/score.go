@@ -0,0 +1,141 @@
+package driver
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/grindlemire/go-lucene/pkg/lucene/expr"
+)
+
+// scoreTerm is a clause that adds weight to the relevance score when it matches.
+type scoreTerm struct {
+	clause *expr.Expression
+	weight float64
+}
+
+// RenderScored renders the expression as a filter along with a relevance score to order the
+// matching rows by. Boosts are dropped from the filter since they never change which rows
+// match. The score sums a CASE WHEN for every clause that isn't negated, weighted by the boosts
+// around it (1 when unboosted), so rows that match more clauses or more heavily boosted ones
+// rank higher, e.g. title:go^2 OR body:go scores as
+//
+//	CASE WHEN "title" = 'go' THEN 2 ELSE 0 END + CASE WHEN "body" = 'go' THEN 1 ELSE 0 END
+func (b Base) RenderScored(e *expr.Expression) (where string, score string, err error) {
+	e, err = b.resolveBareTerms(e)
+	if err != nil {
+		return "", "", err
+	}
+	where, err = b.render(stripBoosts(e))
+	if err != nil {
+		return "", "", err
+	}
+
+	cases := []string{}
+	for _, term := range scoreTerms(e, 1) {
+		clause, err := b.render(stripBoosts(term.clause))
+		if err != nil {
+			return "", "", err
+		}
+		cases = append(cases, scoreCase(clause, term.weight))
+	}
+	return where, joinScore(cases), nil
+}
+
+// RenderScoredParam is the parameterized form of RenderScored. The params hold the values for
+// the filter followed by the values for the score, in the order their placeholders appear in
+// "WHERE <where> ORDER BY <score> DESC".
+func (b Base) RenderScoredParam(e *expr.Expression) (where string, score string, params []any, err error) {
+	return b.renderScoredParam(e, questionMarks)
+}
+
+// renderScoredParam is RenderScoredParam with the placeholders written by format. The ones in the
+// score are numbered after the ones in the filter, so both can go in the same statement.
+func (b Base) renderScoredParam(e *expr.Expression, format placeholderFormat) (where string, score string, params []any, err error) {
+	e, err = b.resolveBareTerms(e)
+	if err != nil {
+		return "", "", nil, err
+	}
+	where, params, err = b.renderParam(stripBoosts(e))
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	cases := []string{}
+	scoreParams := []any{}
+	for _, term := range scoreTerms(e, 1) {
+		clause, cparams, err := b.renderParam(stripBoosts(term.clause))
+		if err != nil {
+			return "", "", nil, err
+		}
+		cases = append(cases, scoreCase(clause, term.weight))
+		scoreParams = append(scoreParams, cparams...)
+	}
+
+	where, next, err := writePlaceholders(where, params, 1, format)
+	if err != nil {
+		return "", "", nil, err
+	}
+	score, _, err = writePlaceholders(joinScore(cases), scoreParams, next, format)
+	if err != nil {
+		return "", "", nil, err
+	}
+	return where, score, append(params, scoreParams...), nil
+}
+
+// scoreTerms collects the clauses that contribute to the score. And, Or and Must are walked
+// through, negated clauses never contribute, and a boost multiplies the weight of everything
+// it wraps.
+func scoreTerms(e *expr.Expression, weight float64) []scoreTerm {
+	if e == nil {
+		return nil
+	}
+
+	switch e.Op {
+	case expr.And, expr.Or:
+		left, _ := e.Left.(*expr.Expression)
+		right, _ := e.Right.(*expr.Expression)
+		return append(scoreTerms(left, weight), scoreTerms(right, weight)...)
+	case expr.Must:
+		left, _ := e.Left.(*expr.Expression)
+		return scoreTerms(left, weight)
+	case expr.Not, expr.MustNot:
+		return nil
+	case expr.Boost:
+		left, _ := e.Left.(*expr.Expression)
+		return scoreTerms(left, weight*e.BoostPower())
+	}
+	return []scoreTerm{{clause: e, weight: weight}}
+}
+
+// stripBoosts returns a copy of the expression with every boost replaced by the expression
+// it wraps. The original expression is left untouched.
+func stripBoosts(e *expr.Expression) *expr.Expression {
+	if e == nil {
+		return nil
+	}
+	if e.Op == expr.Boost {
+		left, _ := e.Left.(*expr.Expression)
+		return stripBoosts(left)
+	}
+
+	cp := *e
+	if left, ok := e.Left.(*expr.Expression); ok {
+		cp.Left = stripBoosts(left)
+	}
+	if right, ok := e.Right.(*expr.Expression); ok {
+		cp.Right = stripBoosts(right)
+	}
+	return &cp
+}
+
+func scoreCase(clause string, weight float64) string {
+	return fmt.Sprintf("CASE WHEN %s THEN %s ELSE 0 END", clause, strconv.FormatFloat(weight, 'f', -1, 64))
+}
+
+func joinScore(cases []string) string {
+	if len(cases) == 0 {
+		return "0"
+	}
+	return strings.Join(cases, " + ")
+}
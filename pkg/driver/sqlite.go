@@ -3,6 +3,7 @@ package driver
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/grindlemire/go-lucene/pkg/lucene/expr"
 )
@@ -56,14 +57,43 @@ func (sqliteDialect) RenderStandaloneWild(left string) (string, error) {
 	return fmt.Sprintf("%s IS NOT NULL", left), nil
 }
 
-// PrepareLikePattern is the identity function for SQLite. Lucene's wildcard
-// syntax (* and ?) is already the same as GLOB's, so no translation is needed,
-// and SQLite never falls back to regex based on pattern content.
-// Note that GLOB has no escape mechanism — patterns containing a literal * or ?
-// cannot be expressed via the wildcard form; users must fall back to the regex
-// form for that.
+// PrepareLikePattern keeps Lucene's wildcard syntax (* and ?), which is already
+// the same as GLOB's, and SQLite never falls back to regex based on pattern
+// content. GLOB has no escape character, so an escaped * or ? becomes a
+// character class holding just that character.
 func (sqliteDialect) PrepareLikePattern(pattern string) (string, bool) {
-	return pattern, false
+	return translateWildcard(pattern, "*", "?", func(r rune) string {
+		if r == '*' || r == '?' {
+			return "[" + string(r) + "]"
+		}
+		return string(r)
+	}), false
+}
+
+func (sqliteDialect) RenderEqualsFold(left, right string) (string, error) {
+	return fmt.Sprintf("%s = %s COLLATE NOCASE", left, right), nil
+}
+
+// PrepareLikePatternFold translates the pattern for LIKE since GLOB is always
+// case-sensitive. Unlike GLOB, LIKE can escape, so a literal % or _ still
+// matches itself.
+func (sqliteDialect) PrepareLikePatternFold(pattern string) (string, bool) {
+	return luceneWildcardToLike(pattern), false
+}
+
+// PrepareRegexpFold prefixes the pattern with (?i), which the Go and PCRE
+// regexp() implementations commonly registered on a connection accept.
+func (sqliteDialect) PrepareRegexpFold(pattern string) string {
+	return "(?i)" + pattern
+}
+
+// RenderLikeFold lowercases both sides of LIKE so the match doesn't depend on
+// PRAGMA case_sensitive_like. Regexes already carry their (?i) flag.
+func (d sqliteDialect) RenderLikeFold(left, right string, isRegex bool) (string, error) {
+	if isRegex {
+		return d.RenderLike(left, right, true)
+	}
+	return fmt.Sprintf("LOWER(%s) LIKE LOWER(%s) ESCAPE '#'", left, right), nil
 }
 
 func (sqliteDialect) EscapeStringLiteral(s string) string {
@@ -84,6 +114,101 @@ func (sqliteDialect) BoolParam(b bool) any {
 	return 0
 }
 
+// sqliteTimestampFormat matches the text that SQLite's own date functions and
+// CURRENT_TIMESTAMP produce, so timestamps stored as TEXT compare correctly.
+const sqliteTimestampFormat = "2006-01-02 15:04:05.999999"
+
+func (sqliteDialect) SerializeTimestamp(t time.Time) string {
+	return "'" + t.UTC().Format(sqliteTimestampFormat) + "'"
+}
+
+// TimestampParam formats the time as TEXT in UTC, since SQLite has no native
+// timestamp type.
+func (sqliteDialect) TimestampParam(t time.Time) any {
+	return t.UTC().Format(sqliteTimestampFormat)
+}
+
+// RenderFuzzy calls levenshtein(column, term). Like regexp, SQLite doesn't
+// define it, so the caller must register it on their connection.
+func (sqliteDialect) RenderFuzzy(left, right string, distance int) (string, error) {
+	return Levenshtein("levenshtein")(left, right, distance)
+}
+
+func (sqliteDialect) FullTextQuery(q *FullTextQuery) (string, error) {
+	return fts5Query(q), nil
+}
+
+// RenderFullText searches an FTS5 table. left is either one of its columns or
+// the table itself, which searches every column.
+func (sqliteDialect) RenderFullText(left, right string, q *FullTextQuery) (string, error) {
+	return fmt.Sprintf("%s MATCH %s", left, right), nil
+}
+
+// fts5Query writes the query in FTS5 syntax. Every term is a quoted string so
+// punctuation and keywords in it are matched literally. FTS5's NOT is binary,
+// so the negated clauses of an AND are subtracted from the rest. A proximity
+// match is a NEAR group, which unlike lucene also matches the words out of
+// order.
+func fts5Query(q *FullTextQuery) string {
+	switch q.Op {
+	case FullTextTerm, FullTextPhrase:
+		return `"` + strings.ReplaceAll(q.Text, `"`, `""`) + `"`
+	case FullTextPrefix:
+		return `"` + strings.ReplaceAll(q.Text, `"`, `""`) + `"*`
+	case FullTextNear:
+		words := []string{}
+		for _, w := range strings.Fields(q.Text) {
+			words = append(words, `"`+strings.ReplaceAll(w, `"`, `""`)+`"`)
+		}
+		return fmt.Sprintf("NEAR(%s, %d)", strings.Join(words, " "), q.Slop)
+	case FullTextOr:
+		return joinFullText(q.Clauses, " OR ", fts5Query)
+	case FullTextNot:
+		return "NOT " + joinFullText(q.Clauses, "", fts5Query)
+	}
+
+	positive, negated := []*FullTextQuery{}, []*FullTextQuery{}
+	for _, c := range q.Clauses {
+		if c.Op == FullTextNot {
+			negated = append(negated, c.Clauses[0])
+			continue
+		}
+		positive = append(positive, c)
+	}
+	s := joinFullText(positive, " AND ", fts5Query)
+	if len(negated) == 0 {
+		return s
+	}
+	if len(positive) > 1 {
+		s = "(" + s + ")"
+	}
+	return s + " NOT " + joinFullText(negated, " NOT ", fts5Query)
+}
+
+// RenderJSONPath uses json_extract, which already returns JSON numbers as SQL
+// numbers and strings as text, so nothing needs casting.
+func (d sqliteDialect) RenderJSONPath(left string, path []string, numeric bool) (string, error) {
+	return fmt.Sprintf("json_extract(%s, %s)", left, d.EscapeStringLiteral(jsonPathString(path))), nil
+}
+
+// RenderArrayContains searches the elements of a JSON array column with
+// json_each since SQLite has no array type.
+func (d sqliteDialect) RenderArrayContains(left string, values []string) (string, error) {
+	source, element := d.ArrayElements(left)
+	if len(values) == 1 {
+		return fmt.Sprintf("EXISTS (SELECT 1 FROM %s WHERE %s = %s)", source, element, values[0]), nil
+	}
+	return fmt.Sprintf("EXISTS (SELECT 1 FROM %s WHERE %s IN (%s))", source, element, strings.Join(values, ", ")), nil
+}
+
+func (sqliteDialect) RenderArrayLength(left string) (string, error) {
+	return fmt.Sprintf("json_array_length(%s)", left), nil
+}
+
+func (sqliteDialect) ArrayElements(left string) (source, element string) {
+	return fmt.Sprintf("json_each(%s)", left), "value"
+}
+
 func (sqliteDialect) QuoteColumn(name string) (string, error) {
 	if strings.ContainsRune(name, '"') {
 		return "", fmt.Errorf("column name contains a double quote: %q", name)
@@ -0,0 +1,148 @@
+package driver
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/grindlemire/go-lucene/pkg/lucene/expr"
+)
+
+// SQLServerDriver transforms a parsed lucene expression to a SQL Server (T-SQL) filter.
+//
+// Notable differences from the Postgres driver:
+//   - Identifiers quote with brackets ([col]). A ] inside a name is doubled.
+//   - Wildcards render as LIKE. Lucene's * and ? become % and _, and the
+//     characters LIKE treats specially (%, _ and [) are escaped by wrapping
+//     them in brackets ([%]), so no ESCAPE clause is needed.
+//   - SQL Server has no native regular expressions, so /pattern/ fails to
+//     render unless SQLServerDialect.RegexFunction names a CLR or user-defined
+//     function to call instead.
+//   - Standalone `field:*` renders as `[field] IS NOT NULL`.
+//   - Parameter placeholders are @p1, @p2, ... as expected by go-mssqldb.
+//   - Bool literals serialize as the BIT values 1/0. Bool params pass through
+//     as Go bool, which go-mssqldb sends as a BIT.
+//   - String literals are unicode (N'...') with single quotes doubled.
+type SQLServerDriver struct {
+	Base
+}
+
+// sqlserverPlaceholder writes SQL Server's @pN placeholders.
+var sqlserverPlaceholder = numbered("@p%d")
+
+// NewSQLServerDriver creates a new driver that will output SQL Server filter strings
+// from parsed lucene expressions. Regular expressions are rejected; to call a regex
+// function instead set the dialect:
+//
+//	d := driver.NewSQLServerDriver()
+//	d.Dialect = driver.SQLServerDialect{RegexFunction: "dbo.RegexIsMatch"}
+func NewSQLServerDriver() SQLServerDriver {
+	fns := map[expr.Operator]RenderFN{}
+	for op, sharedFN := range Shared {
+		fns[op] = sharedFN
+	}
+	return SQLServerDriver{
+		Base: Base{
+			RenderFNs: fns,
+			Dialect:   SQLServerDialect{},
+		},
+	}
+}
+
+// RenderParam will render the expression into a parameterized query using SQL Server's @pN
+// placeholder format. The params are in placeholder order, so they can be passed positionally.
+func (d SQLServerDriver) RenderParam(e *expr.Expression) (s string, params []any, err error) {
+	return d.renderParamWith(e, sqlserverPlaceholder)
+}
+
+// Finalize returns the SQL and arguments of the fragment with @pN placeholders numbered
+// from start, e.g. for a filter placed after the statement's own @p1 and @p2.
+func (d SQLServerDriver) Finalize(f Fragment, start int) (string, []any, error) {
+	return finalize(f, start, sqlserverPlaceholder)
+}
+
+// RenderScoredParam is Base.RenderScoredParam with @pN placeholders.
+func (d SQLServerDriver) RenderScoredParam(e *expr.Expression) (where string, score string, params []any, err error) {
+	return d.renderScoredParam(e, sqlserverPlaceholder)
+}
+
+// SQLServerDialect implements Dialect for SQL Server. It is exported so the regex
+// function can be configured.
+type SQLServerDialect struct {
+	// RegexFunction is the name of a function taking the column and the pattern and
+	// returning 1 on a match, e.g. a CLR function wrapping .NET's Regex.IsMatch. It
+	// renders a:/re/ as RegexFunction([a], N're') = 1. Regular expressions fail to
+	// render when it is empty.
+	RegexFunction string
+}
+
+func (d SQLServerDialect) RenderLike(left, right string, isRegex bool) (string, error) {
+	if isRegex {
+		if d.RegexFunction == "" {
+			return "", fmt.Errorf("SQL Server has no native regular expressions; set SQLServerDialect.RegexFunction to a function that matches them")
+		}
+		return fmt.Sprintf("%s(%s, %s) = 1", d.RegexFunction, left, right), nil
+	}
+	return fmt.Sprintf("%s LIKE %s", left, right), nil
+}
+
+func (SQLServerDialect) RenderStandaloneWild(left string) (string, error) {
+	return fmt.Sprintf("%s IS NOT NULL", left), nil
+}
+
+// PrepareLikePattern translates Lucene wildcard syntax to T-SQL LIKE syntax. The LIKE
+// metacharacters are escaped with brackets first, since brackets are themselves a LIKE
+// character class and a backslash has no special meaning. SQL Server never falls back to
+// the regex path based on pattern content.
+func (SQLServerDialect) PrepareLikePattern(pattern string) (string, bool) {
+	return translateWildcard(pattern, "%", "_", func(r rune) string {
+		if strings.ContainsRune(`[%_`, r) {
+			return "[" + string(r) + "]"
+		}
+		return string(r)
+	}), false
+}
+
+func (SQLServerDialect) EscapeStringLiteral(s string) string {
+	return fmt.Sprintf("N'%s'", strings.ReplaceAll(s, "'", "''"))
+}
+
+func (SQLServerDialect) SerializeBool(b bool) string {
+	if b {
+		return "1"
+	}
+	return "0"
+}
+
+// BoolParam passes the Go bool through directly. go-mssqldb sends bool
+// parameters as BIT.
+func (SQLServerDialect) BoolParam(b bool) any { return b }
+
+// sqlserverTimestampFormat is the datetime2 literal format. Times are converted to UTC
+// first since datetime2 has no zone.
+const sqlserverTimestampFormat = "2006-01-02 15:04:05.9999999"
+
+func (SQLServerDialect) SerializeTimestamp(t time.Time) string {
+	return "'" + t.UTC().Format(sqlserverTimestampFormat) + "'"
+}
+
+// TimestampParam passes the time through directly. go-mssqldb sends time.Time
+// parameters as datetimeoffset.
+func (SQLServerDialect) TimestampParam(t time.Time) any { return t }
+
+// RenderFuzzy falls back to SOUNDEX since SQL Server has no built-in edit
+// distance function. Set Base.Fuzzy to Levenshtein with the name of a
+// user-defined function to honor the distance.
+func (SQLServerDialect) RenderFuzzy(left, right string, distance int) (string, error) {
+	return Soundex()(left, right, distance)
+}
+
+func (SQLServerDialect) QuoteColumn(name string) (string, error) {
+	if len(name) == 0 {
+		return "", fmt.Errorf("column name is empty")
+	}
+	if strings.ContainsRune(name, 0) {
+		return "", fmt.Errorf("column name contains a null byte: %q", name)
+	}
+	return "[" + strings.ReplaceAll(name, "]", "]]") + "]", nil
+}
@@ -0,0 +1,167 @@
+package driver
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grindlemire/go-lucene/pkg/lucene/expr"
+)
+
+func TestSQLServerDriver(t *testing.T) {
+	regexUDF := NewSQLServerDriver()
+	regexUDF.Dialect = SQLServerDialect{RegexFunction: "dbo.RegexIsMatch"}
+
+	type tc struct {
+		driver SQLServerDriver
+		input  *expr.Expression
+		want   string
+		err    string
+	}
+
+	tcs := map[string]tc{
+		"simple_equals": {
+			input: expr.Eq("a", 5),
+			want:  "[a] = 5",
+		},
+		"string_equals": {
+			input: expr.Eq("a", "it's"),
+			want:  "[a] = N'it''s'",
+		},
+		"simple_and": {
+			input: expr.AND(expr.Eq("a", 5), expr.Eq("b", "foo")),
+			want:  "([a] = 5) AND ([b] = N'foo')",
+		},
+		"simple_not": {
+			input: expr.NOT(expr.Eq("a", 1)),
+			want:  "NOT([a] = 1)",
+		},
+		"bracket_in_column": {
+			input: expr.Eq("a]b", 1),
+			want:  "[a]]b] = 1",
+		},
+		"bool_literal": {
+			input: expr.Eq("active", true),
+			want:  "[active] = 1",
+		},
+		"like_wildcard": {
+			input: expr.LIKE("a", "b*"),
+			want:  "[a] LIKE N'b%'",
+		},
+		"like_question": {
+			input: expr.LIKE("a", "b?z"),
+			want:  "[a] LIKE N'b_z'",
+		},
+		"like_escapes_metacharacters": {
+			input: expr.LIKE("a", "100%_[x]*"),
+			want:  "[a] LIKE N'100[%][_][[]x]%'",
+		},
+		"standalone_wild": {
+			input: expr.LIKE("a", "*"),
+			want:  "[a] IS NOT NULL",
+		},
+		"string_range_inclusive": {
+			input: expr.Rang("a", "foo", "bar", true),
+			want:  "[a] BETWEEN N'foo' AND N'bar'",
+		},
+		"timestamp_range": {
+			input: expr.Rang("t", time.Date(2024, 1, 1, 10, 30, 0, 0, time.UTC), expr.WILD("*"), true),
+			want:  "[t] >= '2024-01-01 10:30:00'",
+		},
+		"fuzzy_soundex": {
+			input: expr.FUZZY(expr.Eq("a", "foo"), 1),
+			want:  "SOUNDEX([a]) = SOUNDEX(N'foo')",
+		},
+		"regex_rejected": {
+			input: expr.LIKE("a", expr.REGEXP("/b.*/")),
+			err:   "SQL Server has no native regular expressions",
+		},
+		"regex_function": {
+			driver: regexUDF,
+			input:  expr.LIKE("a", expr.REGEXP("/b.*/")),
+			want:   "dbo.RegexIsMatch([a], N'b.*') = 1",
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			d := tc.driver
+			if d.Dialect == nil {
+				d = NewSQLServerDriver()
+			}
+			got, err := d.Render(tc.input)
+			if tc.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Fatalf(errTemplate, "error doesn't match", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("got an unexpected error when rendering: %v", err)
+			}
+			if tc.want != got {
+				t.Fatalf(errTemplate, "generated sql does not match", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestSQLServerDriverParam(t *testing.T) {
+	type tc struct {
+		input      *expr.Expression
+		wantStr    string
+		wantParams []any
+	}
+
+	tcs := map[string]tc{
+		"numbered_placeholders": {
+			input:      expr.AND(expr.Eq("a", "foo"), expr.Rang("b", 1, 10, true)),
+			wantStr:    "([a] = @p1) AND ([b] >= @p2 AND [b] <= @p3)",
+			wantParams: []any{"foo", 1, 10},
+		},
+		"bool_param": {
+			input:      expr.Eq("active", true),
+			wantStr:    "[active] = @p1",
+			wantParams: []any{true},
+		},
+		"like_param": {
+			input:      expr.LIKE("a", "b_*"),
+			wantStr:    "[a] LIKE @p1",
+			wantParams: []any{"b[_]%"},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			gotStr, gotParams, err := NewSQLServerDriver().RenderParam(tc.input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if gotStr != tc.wantStr {
+				t.Fatalf(errTemplate, "generated sql does not match", tc.wantStr, gotStr)
+			}
+			if !reflect.DeepEqual(gotParams, tc.wantParams) {
+				t.Fatalf("params don't match:\n    wanted %v\n    got    %v", tc.wantParams, gotParams)
+			}
+		})
+	}
+}
+
+func TestSQLServerScoredParam(t *testing.T) {
+	where, score, params, err := NewSQLServerDriver().RenderScoredParam(
+		expr.OR(expr.BOOST(expr.Eq("a", "foo"), 2), expr.Eq("b", "bar")),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "([a] = @p1) OR ([b] = @p2)"; where != want {
+		t.Fatalf(errTemplate, "generated sql does not match", want, where)
+	}
+	if want := "CASE WHEN [a] = @p3 THEN 2 ELSE 0 END + CASE WHEN [b] = @p4 THEN 1 ELSE 0 END"; score != want {
+		t.Fatalf(errTemplate, "generated score does not match", want, score)
+	}
+	if want := []any{"foo", "bar", "foo", "bar"}; !reflect.DeepEqual(params, want) {
+		t.Fatalf("params don't match:\n    wanted %v\n    got    %v", want, params)
+	}
+}
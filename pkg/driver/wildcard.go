@@ -0,0 +1,42 @@
+package driver
+
+import "strings"
+
+// translateWildcard rewrites a lucene wildcard pattern for a database: * becomes many, ? becomes
+// one and every other character is written by literal. A backslash escapes the character after
+// it, so \* and \? are written by literal too, e.g. as the ? of the phrase prefix "what?"*.
+func translateWildcard(pattern, many, one string, literal func(r rune) string) string {
+	var b strings.Builder
+	b.Grow(len(pattern))
+	escaped := false
+	for _, r := range pattern {
+		switch {
+		case escaped:
+			b.WriteString(literal(r))
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '*':
+			b.WriteString(many)
+		case r == '?':
+			b.WriteString(one)
+		default:
+			b.WriteString(literal(r))
+		}
+	}
+	if escaped {
+		b.WriteString(literal('\\'))
+	}
+	return b.String()
+}
+
+// escapeWith returns a literal func for translateWildcard that prefixes the given characters
+// with escape.
+func escapeWith(escape string, chars string) func(r rune) string {
+	return func(r rune) string {
+		if strings.ContainsRune(chars, r) {
+			return escape + string(r)
+		}
+		return string(r)
+	}
+}
@@ -0,0 +1,45 @@
+package driver
+
+import (
+	"testing"
+
+	"github.com/grindlemire/go-lucene/pkg/lucene/expr"
+)
+
+func TestEscapedWildcard(t *testing.T) {
+	// a:"what?"* parses to the wildcard what\?*, whose ? matches itself
+	input := expr.LIKE("a", expr.WILD(`what\?*`))
+
+	type tc struct {
+		driver interface {
+			Render(*expr.Expression) (string, error)
+		}
+		want string
+	}
+
+	tcs := map[string]tc{
+		"postgres":   {driver: NewPostgresDriver(), want: `"a" SIMILAR TO 'what\?%'`},
+		"mysql":      {driver: NewMySQLDriver(), want: "`a` LIKE 'what?%' ESCAPE '#'"},
+		"sqlite":     {driver: NewSQLiteDriver(), want: `"a" GLOB 'what[?]*'`},
+		"sqlserver":  {driver: NewSQLServerDriver(), want: `[a] LIKE N'what?%'`},
+		"oracle":     {driver: NewOracleDriver(), want: `"A" LIKE 'what?%' ESCAPE '\'`},
+		"clickhouse": {driver: NewClickHouseDriver(), want: "`a` LIKE 'what?%'"},
+		"bigquery":   {driver: NewBigQueryDriver(), want: "`a` LIKE 'what?%'"},
+		"elasticsearch": {
+			driver: NewElasticsearchDriver(),
+			want:   `{"query":{"wildcard":{"a":{"value":"what\\?*"}}}}`,
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			got, err := tc.driver.Render(input)
+			if err != nil {
+				t.Fatalf("got an unexpected error when rendering: %v", err)
+			}
+			if got != tc.want {
+				t.Fatalf(errTemplate, "generated sql doesn't match", tc.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,678 @@
+// Package eval matches parsed lucene expressions against in-memory Go values. It follows the
+// semantics of the sql drivers so a filter selects the same records whether it runs in a
+// database or against a cache, including sql's three-valued logic for missing and nil
+// fields (e.g. NOT a:1 does not match a record where a is null).
+package eval
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grindlemire/go-lucene/pkg/lucene/expr"
+)
+
+// Match reports whether the record matches the expression. The record can be a
+// map[string]any or a struct (or a pointer to either). Struct fields are looked up by
+// their `lucene` tag, then their `json` tag, then their Go name. A dotted field such as
+// user.name that isn't found as-is is looked up as a path through nested maps and structs.
+//
+// Fields that are missing from the record are treated as null.
+func Match(e *expr.Expression, record any) (bool, error) {
+	res, err := evaluate(e, reflect.ValueOf(record))
+	if err != nil {
+		return false, err
+	}
+	return res == yes, nil
+}
+
+// truth is a sql three-valued logic result. A comparison against null is unknown and
+// unknown stays unknown under NOT, just like a WHERE clause.
+type truth int
+
+const (
+	unknown truth = iota
+	no
+	yes
+)
+
+func truthOf(b bool) truth {
+	if b {
+		return yes
+	}
+	return no
+}
+
+func (t truth) not() truth {
+	switch t {
+	case yes:
+		return no
+	case no:
+		return yes
+	}
+	return unknown
+}
+
+func and(a, b truth) truth {
+	if a == no || b == no {
+		return no
+	}
+	if a == yes && b == yes {
+		return yes
+	}
+	return unknown
+}
+
+func or(a, b truth) truth {
+	if a == yes || b == yes {
+		return yes
+	}
+	if a == no && b == no {
+		return no
+	}
+	return unknown
+}
+
+type evaluator func(e *expr.Expression, record reflect.Value) (truth, error)
+
+var evaluators map[expr.Operator]evaluator
+
+func init() {
+	evaluators = map[expr.Operator]evaluator{
+		expr.And:       evalAnd,
+		expr.Or:        evalOr,
+		expr.Not:       evalNot,
+		expr.MustNot:   evalNot,
+		expr.Must:      evalWrapped,
+		expr.Boost:     evalWrapped, // boost only affects scoring, not matching
+		expr.Fuzzy:     evalFuzzy,
+		expr.Proximity: evalProximity,
+		expr.Equals:    evalEquals,
+		expr.Like:      evalLike,
+		expr.Range:     evalRange,
+		expr.Greater:   evalCompare,
+		expr.GreaterEq: evalCompare,
+		expr.Less:      evalCompare,
+		expr.LessEq:    evalCompare,
+		expr.In:        evalIn,
+	}
+}
+
+func evaluate(e *expr.Expression, record reflect.Value) (truth, error) {
+	if e == nil {
+		return yes, nil
+	}
+
+	if e.Op == expr.Literal || e.Op == expr.Wild || e.Op == expr.Regexp {
+		return unknown, fmt.Errorf("a term without a field cannot be evaluated: %s", e)
+	}
+
+	fn, ok := evaluators[e.Op]
+	if !ok {
+		return unknown, fmt.Errorf("unable to evaluate operator [%s]", e.Op)
+	}
+	return fn(e, record)
+}
+
+func evaluateSub(in any, record reflect.Value) (truth, error) {
+	e, ok := in.(*expr.Expression)
+	if !ok {
+		return unknown, fmt.Errorf("expected a sub expression, got %T", in)
+	}
+	return evaluate(e, record)
+}
+
+func evalAnd(e *expr.Expression, record reflect.Value) (truth, error) {
+	left, err := evaluateSub(e.Left, record)
+	if err != nil {
+		return unknown, err
+	}
+	right, err := evaluateSub(e.Right, record)
+	if err != nil {
+		return unknown, err
+	}
+	return and(left, right), nil
+}
+
+func evalOr(e *expr.Expression, record reflect.Value) (truth, error) {
+	left, err := evaluateSub(e.Left, record)
+	if err != nil {
+		return unknown, err
+	}
+	right, err := evaluateSub(e.Right, record)
+	if err != nil {
+		return unknown, err
+	}
+	return or(left, right), nil
+}
+
+func evalNot(e *expr.Expression, record reflect.Value) (truth, error) {
+	res, err := evaluateSub(e.Left, record)
+	return res.not(), err
+}
+
+func evalWrapped(e *expr.Expression, record reflect.Value) (truth, error) {
+	return evaluateSub(e.Left, record)
+}
+
+func evalEquals(e *expr.Expression, record reflect.Value) (truth, error) {
+	val, err := fieldValue(e.Left, record)
+	if err != nil {
+		return unknown, err
+	}
+
+	lit, err := literalValue(e.Right)
+	if err != nil {
+		return unknown, err
+	}
+
+	if lit == nil {
+		return truthOf(val == nil), nil
+	}
+	return equal(val, lit), nil
+}
+
+func evalIn(e *expr.Expression, record reflect.Value) (truth, error) {
+	val, err := fieldValue(e.Left, record)
+	if err != nil {
+		return unknown, err
+	}
+
+	list, ok := e.Right.(*expr.Expression)
+	if !ok || list.Op != expr.List {
+		return unknown, fmt.Errorf("in operator requires a list, got %T", e.Right)
+	}
+	items, ok := list.Left.([]*expr.Expression)
+	if !ok {
+		return unknown, fmt.Errorf("list requires a slice of expressions, got %T", list.Left)
+	}
+
+	res := no
+	for _, item := range items {
+		lit, err := literalValue(item)
+		if err != nil {
+			return unknown, err
+		}
+		if lit == nil {
+			res = or(res, truthOf(val == nil))
+			continue
+		}
+		res = or(res, equal(val, lit))
+	}
+	return res, nil
+}
+
+func evalLike(e *expr.Expression, record reflect.Value) (truth, error) {
+	val, err := fieldValue(e.Left, record)
+	if err != nil {
+		return unknown, err
+	}
+
+	right, ok := e.Right.(*expr.Expression)
+	if !ok {
+		return unknown, fmt.Errorf("like requires an expression on the right side, got %T", e.Right)
+	}
+	pattern := fmt.Sprintf("%v", right.Left)
+
+	// field:* only checks that the field has a value
+	if right.Op == expr.Wild && pattern == "*" {
+		return truthOf(val != nil), nil
+	}
+
+	var re *regexp.Regexp
+	if right.Op == expr.Regexp {
+		re, err = regexp.Compile(regexpPattern(pattern))
+	} else {
+		re, err = regexp.Compile(wildcardToRegexp(pattern))
+	}
+	if err != nil {
+		return unknown, err
+	}
+
+	if val == nil {
+		return unknown, nil
+	}
+	return truthOf(re.MatchString(toString(val))), nil
+}
+
+func evalRange(e *expr.Expression, record reflect.Value) (truth, error) {
+	val, err := fieldValue(e.Left, record)
+	if err != nil {
+		return unknown, err
+	}
+
+	boundary, ok := e.Right.(*expr.RangeBoundary)
+	if !ok {
+		return unknown, fmt.Errorf("range operator requires *expr.RangeBoundary, got %T", e.Right)
+	}
+
+	res := yes
+	for _, b := range []struct {
+		bound any
+		lower bool
+	}{{boundary.Min, true}, {boundary.Max, false}} {
+		bound, ok := b.bound.(*expr.Expression)
+		if !ok {
+			return unknown, fmt.Errorf("range bound must be an expression, got %T", b.bound)
+		}
+		if bound.Op == expr.Null {
+			return unknown, fmt.Errorf("null is not allowed as a range bound; use field:null for IS NULL")
+		}
+		// an unbounded side always holds, so [* TO *] matches nulls too just like the
+		// 1=1 the sql drivers render for it
+		if bound.Op == expr.Wild {
+			continue
+		}
+
+		if val == nil {
+			return unknown, nil
+		}
+		cmp, ok := compare(val, bound.Left)
+		if !ok {
+			return no, nil
+		}
+		if !b.lower {
+			cmp = -cmp
+		}
+		res = and(res, truthOf(cmp > 0 || (cmp == 0 && boundary.Inclusive)))
+	}
+
+	return res, nil
+}
+
+func evalCompare(e *expr.Expression, record reflect.Value) (truth, error) {
+	val, err := fieldValue(e.Left, record)
+	if err != nil {
+		return unknown, err
+	}
+
+	lit, err := literalValue(e.Right)
+	if err != nil {
+		return unknown, err
+	}
+	if lit == nil {
+		return unknown, fmt.Errorf(
+			"comparison operator %s cannot be used with null; use field:null for IS NULL",
+			e.Op,
+		)
+	}
+
+	if val == nil {
+		return unknown, nil
+	}
+	cmp, ok := compare(val, lit)
+	if !ok {
+		return no, nil
+	}
+
+	switch e.Op {
+	case expr.Greater:
+		return truthOf(cmp > 0), nil
+	case expr.GreaterEq:
+		return truthOf(cmp >= 0), nil
+	case expr.Less:
+		return truthOf(cmp < 0), nil
+	default:
+		return truthOf(cmp <= 0), nil
+	}
+}
+
+// evalFuzzy matches a term within the fuzzy edit distance of the record's value.
+func evalFuzzy(e *expr.Expression, record reflect.Value) (truth, error) {
+	inner, ok := e.Left.(*expr.Expression)
+	if !ok || inner.Op != expr.Equals {
+		return unknown, fmt.Errorf("fuzzy can only be applied to a field:term expression")
+	}
+
+	val, err := fieldValue(inner.Left, record)
+	if err != nil {
+		return unknown, err
+	}
+	lit, err := literalValue(inner.Right)
+	if err != nil {
+		return unknown, err
+	}
+	if val == nil || lit == nil {
+		return unknown, nil
+	}
+
+	return truthOf(levenshtein(toString(val), toString(lit)) <= e.FuzzyDistance()), nil
+}
+
+// evalProximity matches a phrase against the words of the value, like a full-text phrase search:
+// the words of the phrase must appear in order with at most slop other words between them in
+// total, so "quick fox"~1 matches "the quick brown fox". It is conservative and never matches
+// the words out of order, which lucene allows for a larger slop.
+func evalProximity(e *expr.Expression, record reflect.Value) (truth, error) {
+	inner, ok := e.Left.(*expr.Expression)
+	if !ok || inner.Op != expr.Equals {
+		return unknown, fmt.Errorf("proximity can only be applied to a field:\"phrase\" expression")
+	}
+
+	val, err := fieldValue(inner.Left, record)
+	if err != nil {
+		return unknown, err
+	}
+	lit, err := literalValue(inner.Right)
+	if err != nil {
+		return unknown, err
+	}
+	if val == nil || lit == nil {
+		return unknown, nil
+	}
+
+	gaps, found := phraseGaps(strings.Fields(toString(val)), strings.Fields(toString(lit)))
+	return truthOf(found && gaps <= e.Slop()), nil
+}
+
+// literalValue unwraps the value of a literal expression. Null is returned as nil.
+func literalValue(in any) (any, error) {
+	e, ok := in.(*expr.Expression)
+	if !ok {
+		return nil, fmt.Errorf("expected a literal expression, got %T", in)
+	}
+	switch e.Op {
+	case expr.Null:
+		return nil, nil
+	case expr.Literal, expr.Wild, expr.Regexp:
+		return e.Left, nil
+	}
+	return nil, fmt.Errorf("expected a literal expression, got %s", e.Op)
+}
+
+// fieldValue looks up the value of the column in the record and normalizes it. Missing
+// fields and nil values are returned as nil.
+func fieldValue(in any, record reflect.Value) (any, error) {
+	e, ok := in.(*expr.Expression)
+	if !ok {
+		return nil, fmt.Errorf("expected a field, got %T", in)
+	}
+
+	var name string
+	switch v := e.Left.(type) {
+	case expr.Column:
+		name = string(v)
+	case expr.RawColumn:
+		name = string(v)
+	default:
+		return nil, fmt.Errorf("a term without a field cannot be evaluated: %v", e.Left)
+	}
+	if name == "" {
+		return nil, fmt.Errorf("column name is empty")
+	}
+
+	v, found := lookup(record, name)
+	if !found {
+		// fall back to walking a dotted path through nested values
+		parts := strings.Split(name, ".")
+		v = record
+		for _, part := range parts {
+			if v, found = lookup(v, part); !found {
+				return nil, nil
+			}
+		}
+	}
+	return normalize(v), nil
+}
+
+func lookup(record reflect.Value, name string) (reflect.Value, bool) {
+	record = indirect(record)
+	switch record.Kind() {
+	case reflect.Map:
+		if record.Type().Key().Kind() != reflect.String {
+			return reflect.Value{}, false
+		}
+		v := record.MapIndex(reflect.ValueOf(name).Convert(record.Type().Key()))
+		return v, v.IsValid()
+	case reflect.Struct:
+		t := record.Type()
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			if fieldName := structFieldName(f); fieldName != "" && fieldName == name {
+				return record.Field(i), true
+			}
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// structFieldName returns the name a struct field is matched by, or "" if the field is
+// excluded with a "-" tag.
+func structFieldName(f reflect.StructField) string {
+	for _, tag := range []string{"lucene", "json"} {
+		name, _, _ := strings.Cut(f.Tag.Get(tag), ",")
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+	return f.Name
+}
+
+func indirect(v reflect.Value) reflect.Value {
+	for v.IsValid() && (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+// normalize converts a record value to one of nil, int64, uint64, float64, bool, string or
+// time.Time.
+// Any other type is compared through its fmt representation.
+func normalize(v reflect.Value) any {
+	v = indirect(v)
+	if !v.IsValid() {
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint()
+	case reflect.Float32, reflect.Float64:
+		return v.Float()
+	case reflect.Bool:
+		return v.Bool()
+	case reflect.String:
+		return v.String()
+	}
+
+	if t, ok := v.Interface().(time.Time); ok {
+		return t
+	}
+	if s, ok := v.Interface().(fmt.Stringer); ok {
+		return s.String()
+	}
+	return fmt.Sprintf("%v", v.Interface())
+}
+
+// equal compares a record value with a literal, converting between numbers, bools and
+// their string forms the way a database would coerce a literal to the column type.
+func equal(val, lit any) truth {
+	if val == nil {
+		return unknown
+	}
+	if b, ok := val.(bool); ok {
+		lb, err := strconv.ParseBool(toString(lit))
+		return truthOf(err == nil && b == lb)
+	}
+
+	cmp, ok := compare(val, lit)
+	return truthOf(ok && cmp == 0)
+}
+
+// compare orders a record value against a literal. Numbers compare numerically (parsing
+// strings when the other side is a number), times compare chronologically (parsing RFC 3339
+// and date strings) and everything else compares as strings.
+func compare(val, lit any) (int, bool) {
+	vf, vNum := toFloat(val)
+	lf, lNum := toFloat(lit)
+	_, vStr := val.(string)
+	_, lStr := lit.(string)
+
+	if lt, ok := lit.(time.Time); ok {
+		vt, ok := toTime(val)
+		return vt.Compare(lt), ok
+	}
+
+	switch {
+	case vNum && lNum:
+		return compareFloat(vf, lf), true
+	case vNum && lStr:
+		f, err := strconv.ParseFloat(lit.(string), 64)
+		return compareFloat(vf, f), err == nil
+	case vStr && lNum:
+		f, err := strconv.ParseFloat(val.(string), 64)
+		return compareFloat(f, lf), err == nil
+	}
+	return strings.Compare(toString(val), toString(lit)), true
+}
+
+// timeLayouts are the string forms of a time that a record value is parsed from when it is
+// compared against a timestamp.
+var timeLayouts = []string{time.RFC3339Nano, "2006-01-02 15:04:05.999999999", "2006-01-02T15:04:05.999999999", "2006-01-02"}
+
+func toTime(v any) (time.Time, bool) {
+	switch t := v.(type) {
+	case time.Time:
+		return t, true
+	case string:
+		for _, layout := range timeLayouts {
+			if parsed, err := time.Parse(layout, t); err == nil {
+				return parsed, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+func compareFloat(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func toFloat(in any) (float64, bool) {
+	switch v := in.(type) {
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
+
+func toString(in any) string {
+	if s, ok := in.(string); ok {
+		return s
+	}
+	return fmt.Sprintf("%v", in)
+}
+
+// regexpPattern removes surrounding /.../ delimiters from a Lucene regexp
+// literal, returning the inner pattern. The i flag (/pattern/i) becomes a
+// case-insensitive (?i) prefix.
+func regexpPattern(s string) string {
+	pattern, flags := expr.SplitRegexp(s)
+	if strings.Contains(flags, "i") {
+		return "(?i)" + pattern
+	}
+	return pattern
+}
+
+// wildcardToRegexp translates a lucene wildcard pattern into an anchored regexp. * matches
+// any run of characters, ? matches exactly one and a backslash escapes the next character.
+func wildcardToRegexp(pattern string) string {
+	var b strings.Builder
+	b.WriteString("^(?s:")
+	escaped := false
+	for _, r := range pattern {
+		switch {
+		case escaped:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '*':
+			b.WriteString(".*")
+		case r == '?':
+			b.WriteString(".")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	b.WriteString(")$")
+	return b.String()
+}
+
+// phraseGaps returns the fewest words between the words of the phrase when they appear in order
+// in words, and whether they appear at all. Taking the earliest next word from each start keeps
+// the span as short as it can be.
+func phraseGaps(words, phrase []string) (int, bool) {
+	if len(phrase) == 0 {
+		return 0, true
+	}
+
+	best, found := 0, false
+	for start, word := range words {
+		if word != phrase[0] {
+			continue
+		}
+		pos, matched := start, 1
+		for i := start + 1; i < len(words) && matched < len(phrase); i++ {
+			if words[i] == phrase[matched] {
+				pos, matched = i, matched+1
+			}
+		}
+		if matched < len(phrase) {
+			break
+		}
+		if gaps := pos - start - (len(phrase) - 1); !found || gaps < best {
+			best, found = gaps, true
+		}
+	}
+	return best, found
+}
+
+// levenshtein returns the edit distance between two strings, counted in runes.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
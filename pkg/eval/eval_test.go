@@ -0,0 +1,137 @@
+package eval_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grindlemire/go-lucene"
+	"github.com/grindlemire/go-lucene/pkg/eval"
+)
+
+type post struct {
+	Title  string   `json:"title"`
+	Views  int      `lucene:"views"`
+	Score  float64  `json:"score,omitempty"`
+	Author *string  `json:"author"`
+	Public bool     `json:"public"`
+	Tags   []string `json:"-"`
+	Meta   meta     `json:"meta"`
+}
+
+type meta struct {
+	Lang string `json:"lang"`
+}
+
+func TestMatch(t *testing.T) {
+	alice := "alice"
+	record := map[string]any{
+		"a":      "foo",
+		"b":      5,
+		"c":      2.5,
+		"d":      nil,
+		"phrase": "hello world",
+		"p":      "the quick brown fox",
+		"flag":   true,
+		"nested": map[string]any{"key": "value"},
+	}
+	st := &post{Title: "Go Lucene", Views: 42, Score: 0.5, Author: &alice, Public: true, Meta: meta{Lang: "en"}}
+
+	type tc struct {
+		input  string
+		record any
+		want   bool
+		err    string
+	}
+
+	tcs := map[string]tc{
+		"equals":                 {input: "a:foo", record: record, want: true},
+		"equals_mismatch":        {input: "a:bar", record: record, want: false},
+		"equals_is_exact":        {input: "a:FOO", record: record, want: false},
+		"equals_number":          {input: "b:5", record: record, want: true},
+		"equals_float":           {input: "c:2.5", record: record, want: true},
+		"equals_phrase":          {input: `phrase:"hello world"`, record: record, want: true},
+		"equals_bool":            {input: "flag:true", record: record, want: true},
+		"and":                    {input: "a:foo AND b:5", record: record, want: true},
+		"and_false":              {input: "a:foo AND b:6", record: record, want: false},
+		"or":                     {input: "a:bar OR b:5", record: record, want: true},
+		"not":                    {input: "NOT a:bar", record: record, want: true},
+		"must_not":               {input: "b:5 -a:foo", record: record, want: false},
+		"must":                   {input: "+a:foo", record: record, want: true},
+		"wildcard":               {input: "a:f*", record: record, want: true},
+		"wildcard_is_anchored":   {input: "a:o*", record: record, want: false},
+		"wildcard_single_char":   {input: "a:f?o", record: record, want: true},
+		"wildcard_escaped":       {input: `a:fo\*`, record: record, want: false},
+		"standalone_wildcard":    {input: "a:*", record: record, want: true},
+		"standalone_wild_null":   {input: "d:*", record: record, want: false},
+		"regexp":                 {input: "a:/o+/", record: record, want: true},
+		"regexp_mismatch":        {input: "a:/^o/", record: record, want: false},
+		"regexp_is_exact":        {input: "a:/FO+/", record: record, want: false},
+		"regexp_ignore_case":     {input: "a:/FO+/i", record: record, want: true},
+		"inclusive_range":        {input: "b:[1 TO 5]", record: record, want: true},
+		"exclusive_range":        {input: "b:{1 TO 5}", record: record, want: false},
+		"open_range":             {input: "b:[* TO 10]", record: record, want: true},
+		"string_range":           {input: "a:[bar TO goo]", record: record, want: true},
+		"unbounded_range_null":   {input: "d:[* TO *]", record: record, want: true},
+		"greater":                {input: "b:>4", record: record, want: true},
+		"greater_eq":             {input: "b:>=5", record: record, want: true},
+		"less":                   {input: "c:<2.5", record: record, want: false},
+		"less_eq":                {input: "c:<=2.5", record: record, want: true},
+		"in":                     {input: "b:(1 OR 5)", record: record, want: true},
+		"in_mismatch":            {input: "b:(1 OR 2)", record: record, want: false},
+		"null":                   {input: "d:null", record: record, want: true},
+		"missing_is_null":        {input: "missing:null", record: record, want: true},
+		"not_null":               {input: "NOT a:null", record: record, want: true},
+		"in_with_null":           {input: "d:(x OR null)", record: record, want: true},
+		"null_is_unknown":        {input: "d:foo", record: record, want: false},
+		"not_null_is_unknown":    {input: "NOT d:foo", record: record, want: false},
+		"or_rescues_unknown":     {input: "NOT d:foo OR a:foo", record: record, want: true},
+		"nested_path":            {input: "nested.key:value", record: record, want: true},
+		"fuzzy":                  {input: "a:fob~1", record: record, want: true},
+		"fuzzy_too_far":          {input: "a:bar~1", record: record, want: false},
+		"proximity_exact_phrase": {input: `p:"quick brown"~0`, record: record, want: true},
+		"proximity_within_slop":  {input: `p:"quick fox"~1`, record: record, want: true},
+		"proximity_too_far":      {input: `p:"the fox"~1`, record: record, want: false},
+		"proximity_out_of_order": {input: `p:"fox quick"~5`, record: record, want: false},
+		"proximity_missing_word": {input: `p:"quick dog"~5`, record: record, want: false},
+		"proximity_null":         {input: `d:"quick fox"~1`, record: record, want: false},
+		"boost_ignored":          {input: "a:foo^2", record: record, want: true},
+		"struct_json_tag":        {input: `title:"Go Lucene"`, record: st, want: true},
+		"struct_lucene_tag":      {input: "views:[40 TO 50]", record: st, want: true},
+		"struct_omitempty_tag":   {input: "score:0.5", record: st, want: true},
+		"struct_pointer_field":   {input: "author:alice", record: st, want: true},
+		"struct_bool":            {input: "public:true", record: st, want: true},
+		"struct_nested":          {input: "meta.lang:en", record: st, want: true},
+		"struct_ignored_field":   {input: "Tags:*", record: st, want: false},
+		"comparison_with_null":   {input: "b:>null", record: record, err: "cannot be used with null"},
+		"term_without_field":     {input: "foo", record: record, err: "without a field"},
+		"invalid_regexp":         {input: "a:/(/", record: record, err: "missing closing )"},
+		"number_matches_string":  {input: "s:10", record: map[string]any{"s": "10"}, want: true},
+		"date_matches_string":    {input: "s:[2024-03-01 TO 2024-04-01}", record: map[string]any{"s": "2024-03-05T10:00:00Z"}, want: true},
+		"date_matches_time":      {input: "s:<2024-03-01", record: map[string]any{"s": time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)}, want: true},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			e, err := lucene.Parse(tc.input)
+			if err != nil {
+				t.Fatalf("unable to parse expression: %v", err)
+			}
+
+			got, err := eval.Match(e, tc.record)
+			if err != nil {
+				if tc.err != "" && strings.Contains(err.Error(), tc.err) {
+					return
+				}
+				t.Fatalf("unexpected error evaluating expression: %v", err)
+			}
+			if tc.err != "" {
+				t.Fatalf("expected error [%s] but got none", tc.err)
+			}
+
+			if got != tc.want {
+				t.Fatalf("Match(%s) = %v, want %v\nparsed expression: %#v", tc.input, got, tc.want, e)
+			}
+		})
+	}
+}
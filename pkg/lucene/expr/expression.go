@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"unicode"
 )
 
 // Lucene Grammar:
@@ -39,6 +40,7 @@ type Expression struct {
 	// these are operator specific states we have to track
 	boostPower    float64
 	fuzzyDistance int
+	slop          int
 }
 
 // RangeBoundary represents the boundary conditions for a range operator
@@ -175,6 +177,29 @@ func FUZZY(e any, distance ...int) *Expression {
 	return Expr(e, Fuzzy)
 }
 
+// PROXIMITY wraps a phrase in a proximity match, allowing its words to be up to slop positions
+// further apart than in the phrase (e.g. a:"quick fox"~5)
+func PROXIMITY(e any, slop int) *Expression {
+	return Expr(e, Proximity, slop)
+}
+
+// BoostPower returns the power of a BOOST expression. It is 1 unless a power was given (e.g. a:b^2).
+func (e *Expression) BoostPower() float64 {
+	return e.boostPower
+}
+
+// FuzzyDistance returns the edit distance of a FUZZY expression. It is 1 unless a distance was given
+// (e.g. a:b~2).
+func (e *Expression) FuzzyDistance() int {
+	return e.fuzzyDistance
+}
+
+// Slop returns how many positions further apart than in the phrase the words of a PROXIMITY
+// expression can be. It is 0 unless a slop was given (e.g. a:"quick fox"~5).
+func (e *Expression) Slop() int {
+	return e.slop
+}
+
 // IsExpr checks if the input is an expression
 func IsExpr(in any) bool {
 	_, isExpr := in.(*Expression)
@@ -214,6 +239,16 @@ func (c Column) GoString() string {
 	return fmt.Sprintf("COLUMN(%s)", c)
 }
 
+// RawColumn is a trusted sql expression used in place of a column (e.g. users.display_name or
+// lower(name)). Unlike Column it is emitted verbatim by the sql drivers, so it must only ever come
+// from application configuration and never from user input.
+type RawColumn string
+
+// GoString is a debug print for the raw column type
+func (c RawColumn) GoString() string {
+	return fmt.Sprintf("RAW_COLUMN(%s)", c)
+}
+
 // Expr creates a general new expression. The other public functions are just helpers that call this
 // function underneath.
 func Expr(left any, op Operator, right ...any) *Expression {
@@ -260,6 +295,14 @@ func Expr(left any, op Operator, right ...any) *Expression {
 		return e
 	}
 
+	// support setting the slop of a proximity match
+	if op == Proximity {
+		if len(right) == 1 && isInt(right[0]) {
+			e.slop = right[0].(int)
+		}
+		return e
+	}
+
 	// support passing a range with inclusivity
 	if op == Range && len(right) == 3 && isBool(right[2]) {
 		e.Right = &RangeBoundary{
@@ -312,6 +355,7 @@ type jsonExpression struct {
 
 	RangeBoundary *RangeBoundary `json:"boundaries,omitempty"`
 	FuzzyDistance *int           `json:"distance,omitempty"`
+	Slop          *int           `json:"slop,omitempty"`
 	BoostPower    *float64       `json:"power,omitempty"`
 }
 
@@ -350,6 +394,10 @@ func (e Expression) MarshalJSON() (out []byte, err error) {
 		c.FuzzyDistance = &e.fuzzyDistance
 	}
 
+	if e.Op == Proximity && e.slop != 0 {
+		c.Slop = &e.slop
+	}
+
 	return json.Marshal(c)
 }
 
@@ -441,6 +489,10 @@ func (e *Expression) UnmarshalJSON(data []byte) (err error) {
 		}
 	}
 
+	if e.Op == Proximity && c.Slop != nil {
+		e.slop = *c.Slop
+	}
+
 	return nil
 }
 
@@ -508,11 +560,11 @@ func literalToExpr(in any) *Expression {
 		return Lit(s)
 	}
 
-	// if it has leading and trailing /'s then it probably is a regex.
-	// Note this needs to be checked before the wildcard check as a regex
-	// can contain * and ?.
+	// if it has leading and trailing /'s (optionally followed by flags) then it
+	// probably is a regex. Note this needs to be checked before the wildcard check
+	// as a regex can contain * and ?.
 	// TODO this should probably check for escaping
-	if len(s) >= 2 && s[0] == '/' && s[len(s)-1] == '/' {
+	if isRegexpLiteral(s) {
 		return REGEXP(s)
 	}
 
@@ -525,6 +577,39 @@ func literalToExpr(in any) *Expression {
 	return Lit(s)
 }
 
+// SplitRegexp splits a lucene regexp such as /ab+c/i into its pattern and flags. The only flag
+// is i, which makes the match case-insensitive. A value without /.../ delimiters is returned
+// unchanged with no flags.
+func SplitRegexp(s string) (pattern string, flags string) {
+	if !isRegexpLiteral(s) {
+		return s, ""
+	}
+	if strings.HasSuffix(s, "/i") {
+		return s[1 : len(s)-2], "i"
+	}
+	return s[1 : len(s)-1], ""
+}
+
+// phrasePrefix returns the phrase of a phrase prefix wildcard such as "quick fo"*, which
+// matches the words of the phrase with the last one as a prefix.
+func phrasePrefix(e *Expression) (string, bool) {
+	s, isStr := e.Left.(string)
+	if e.Op != Wild || !isStr {
+		return "", false
+	}
+	prefix, found := strings.CutSuffix(s, "*")
+	if !found || strings.ContainsAny(prefix, "*?") || !strings.ContainsFunc(prefix, unicode.IsSpace) {
+		return "", false
+	}
+	return prefix, true
+}
+
+// isRegexpLiteral checks if the string is delimited like a regexp, /.../ or /.../i.
+func isRegexpLiteral(s string) bool {
+	s = strings.TrimSuffix(s, "i")
+	return len(s) >= 2 && s[0] == '/' && s[len(s)-1] == '/'
+}
+
 func isJSONObject(in json.RawMessage) bool {
 	trimmed := bytes.TrimSpace(in)
 	if len(trimmed) == 0 {
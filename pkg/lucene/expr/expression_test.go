@@ -141,6 +141,18 @@ func TestExprJSON(t *testing.T) {
 			}`,
 			want: FUZZY("a", 2),
 		},
+		"flat_proximity": {
+			input: `{
+				"left": {
+					"left": "a",
+					"operator": "EQUALS",
+					"right": "quick fox"
+				},
+				"operator": "PROXIMITY",
+				"slop": 5
+			}`,
+			want: PROXIMITY(Eq("a", "quick fox"), 5),
+		},
 		"flat_in_list": {
 			input: `{
 				"left": "a",
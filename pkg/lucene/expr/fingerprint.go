@@ -0,0 +1,149 @@
+package expr
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Canonical returns the canonical form of the expression: chains of ANDs and ORs are flattened,
+// their clauses and the values of IN lists are sorted and duplicates are dropped, and the chains
+// are rebuilt left to right. Queries that only differ in the order of AND, OR and IN clauses,
+// how they are grouped, whitespace, redundant parentheses or an implicit rather than an explicit
+// AND parse to the same canonical form. The input expression is never modified.
+func Canonical(e *Expression) *Expression {
+	out, _ := canonical(e)
+	return out
+}
+
+// Fingerprint returns a stable hash of the canonical form of the expression, e.g. to key a cache
+// of query results so that a:1 AND b:2 and b:2 a:1 share an entry. Unlike the String form it
+// tells the types of values apart, so a:1 and a:"1" have different fingerprints.
+func Fingerprint(e *Expression) string {
+	_, key := canonical(e)
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// canonical returns the canonical form of the expression and a key that fully describes it.
+func canonical(e *Expression) (*Expression, string) {
+	if e == nil {
+		return nil, "NIL"
+	}
+
+	switch e.Op {
+	case And, Or:
+		type clause struct {
+			e   *Expression
+			key string
+		}
+		clauses := []clause{}
+		for _, c := range chainClauses(e, e.Op) {
+			flat, key := canonical(c)
+			clauses = append(clauses, clause{e: flat, key: key})
+		}
+		slices.SortFunc(clauses, func(a, b clause) int { return strings.Compare(a.key, b.key) })
+		clauses = slices.CompactFunc(clauses, func(a, b clause) bool { return a.key == b.key })
+
+		out := clauses[0].e
+		keys := []string{clauses[0].key}
+		for _, c := range clauses[1:] {
+			out = Expr(out, e.Op, c.e)
+			keys = append(keys, c.key)
+		}
+		if len(keys) == 1 {
+			return out, keys[0]
+		}
+		return out, fmt.Sprintf("%s(%s)", e.Op, strings.Join(keys, ","))
+	case List:
+		items, ok := e.Left.([]*Expression)
+		if !ok {
+			break
+		}
+		type item struct {
+			e   *Expression
+			key string
+		}
+		sorted := []item{}
+		for _, i := range items {
+			c, key := canonical(i)
+			sorted = append(sorted, item{e: c, key: key})
+		}
+		slices.SortFunc(sorted, func(a, b item) int { return strings.Compare(a.key, b.key) })
+		sorted = slices.CompactFunc(sorted, func(a, b item) bool { return a.key == b.key })
+
+		cp := *e
+		list := []*Expression{}
+		keys := []string{}
+		for _, i := range sorted {
+			list = append(list, i.e)
+			keys = append(keys, i.key)
+		}
+		cp.Left = list
+		return &cp, fmt.Sprintf("%s(%s)", e.Op, strings.Join(keys, ","))
+	}
+
+	cp := *e
+	left, leftKey := canonicalSide(e.Left)
+	right, rightKey := canonicalSide(e.Right)
+	cp.Left, cp.Right = left, right
+
+	key := fmt.Sprintf("%s(%s", e.Op, leftKey)
+	if e.Right != nil {
+		key += "," + rightKey
+	}
+	switch e.Op {
+	case Boost:
+		key += "," + strconv.FormatFloat(e.boostPower, 'g', -1, 64)
+	case Fuzzy:
+		key += "," + strconv.Itoa(e.fuzzyDistance)
+	case Proximity:
+		key += "," + strconv.Itoa(e.slop)
+	}
+	return &cp, key + ")"
+}
+
+// canonicalSide returns the canonical form of one side of an expression and its key.
+func canonicalSide(side any) (any, string) {
+	switch v := side.(type) {
+	case *Expression:
+		return canonical(v)
+	case *RangeBoundary:
+		if v == nil {
+			return side, "NIL"
+		}
+		boundary := *v
+		_, minKey := canonicalSide(v.Min)
+		_, maxKey := canonicalSide(v.Max)
+		return &boundary, fmt.Sprintf("BOUNDS(%s,%s,%t)", minKey, maxKey, v.Inclusive)
+	}
+	return side, valueKey(side)
+}
+
+// valueKey writes a value with its type, so values that print the same, such as the column a and
+// the string "a" or the number 1 and the string "1", get different keys.
+func valueKey(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "NIL"
+	case Column:
+		return "COLUMN(" + strconv.Quote(string(v)) + ")"
+	case RawColumn:
+		return "RAW_COLUMN(" + strconv.Quote(string(v)) + ")"
+	case string:
+		return strconv.Quote(v)
+	case int:
+		return "INT(" + strconv.Itoa(v) + ")"
+	case float64:
+		return "FLOAT(" + strconv.FormatFloat(v, 'g', -1, 64) + ")"
+	case bool:
+		return "BOOL(" + strconv.FormatBool(v) + ")"
+	case time.Time:
+		return "TIME(" + v.UTC().Format(time.RFC3339Nano) + ")"
+	}
+	return fmt.Sprintf("%T(%v)", v, v)
+}
@@ -0,0 +1,52 @@
+package expr
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCanonical(t *testing.T) {
+	type tc struct {
+		input *Expression
+		want  *Expression
+	}
+
+	tcs := map[string]tc{
+		"sorts_and_flattens": {
+			input: AND(Eq("c", 3), AND(Eq("b", 2), Eq("a", 1))),
+			want:  AND(AND(Eq("a", 1), Eq("b", 2)), Eq("c", 3)),
+		},
+		"sorts_inside_not": {
+			input: NOT(OR(Eq("b", 2), Eq("a", 1))),
+			want:  NOT(OR(Eq("a", 1), Eq("b", 2))),
+		},
+		"sorts_list": {
+			input: IN("a", LIST(Lit("y"), Lit("x"), Lit("y"))),
+			want:  IN("a", LIST(Lit("x"), Lit("y"))),
+		},
+		"drops_duplicates": {
+			input: OR(Eq("a", 1), Eq("a", 1)),
+			want:  Eq("a", 1),
+		},
+		"keeps_boost_power": {
+			input: BOOST(AND(Eq("b", 2), Eq("a", 1)), 2),
+			want:  BOOST(AND(Eq("a", 1), Eq("b", 2)), 2),
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			before := tc.input.String()
+			got := Canonical(tc.input)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf(errTemplate, "canonical expression doesn't match", tc.want, got)
+			}
+			if after := tc.input.String(); after != before {
+				t.Fatalf("Canonical modified its input\nbefore %s\nafter  %s", before, after)
+			}
+			if Fingerprint(tc.input) != Fingerprint(tc.want) {
+				t.Fatalf("expected %s and %s to have the same fingerprint", tc.input, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,301 @@
+package expr
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// ToLucene serializes the expression back into a Lucene query string. The output is canonical:
+// operators are always spelled out (AND, OR, NOT), boosts and fuzzy distances are always
+// explicit and sub expressions are only wrapped in parentheses where precedence requires it,
+// so parsing the result yields an expression equal to e. Values that would otherwise be lexed
+// as something else (numbers stored as strings, keywords, phrases, special characters) are
+// quoted and field names are escaped.
+//
+// Raw columns can't be expressed in Lucene syntax and return an error.
+func ToLucene(e *Expression) (string, error) {
+	if e == nil {
+		return "", fmt.Errorf("unable to serialize a nil expression")
+	}
+	return toLucene(e)
+}
+
+func toLucene(e *Expression) (string, error) {
+	switch e.Op {
+	case Literal, Wild, Regexp, Null:
+		return luceneValue(e)
+	case Equals, Like:
+		return luceneTerm(e, ":")
+	case Greater:
+		return luceneTerm(e, ":>")
+	case GreaterEq:
+		return luceneTerm(e, ":>=")
+	case Less:
+		return luceneTerm(e, ":<")
+	case LessEq:
+		return luceneTerm(e, ":<=")
+	case Range:
+		return luceneRange(e)
+	case In:
+		return luceneIn(e)
+	case And, Or:
+		return luceneCompound(e)
+	case Not:
+		return lucenePrefix(e, "NOT ")
+	case Must:
+		return lucenePrefix(e, "+")
+	case MustNot:
+		return lucenePrefix(e, "-")
+	case Boost:
+		return luceneSuffix(e, "^"+strconv.FormatFloat(e.boostPower, 'f', -1, 64))
+	case Fuzzy:
+		return luceneSuffix(e, "~"+strconv.Itoa(e.fuzzyDistance))
+	case Proximity:
+		return luceneSuffix(e, "~"+strconv.Itoa(e.slop))
+	}
+	return "", fmt.Errorf("unable to serialize operator [%s] to lucene", e.Op)
+}
+
+// luceneTerm serializes field<sep>value for equality, wildcard and comparison terms.
+func luceneTerm(e *Expression, sep string) (string, error) {
+	field, err := luceneField(e.Left)
+	if err != nil {
+		return "", err
+	}
+	right, ok := e.Right.(*Expression)
+	if !ok {
+		return "", fmt.Errorf("unable to serialize %s value of type %T to lucene", e.Op, e.Right)
+	}
+	value, err := luceneValue(right)
+	if err != nil {
+		return "", err
+	}
+	return field + sep + value, nil
+}
+
+func luceneRange(e *Expression) (string, error) {
+	field, err := luceneField(e.Left)
+	if err != nil {
+		return "", err
+	}
+	boundary, ok := e.Right.(*RangeBoundary)
+	if !ok || boundary == nil {
+		return "", fmt.Errorf("range requires a boundary, got %T", e.Right)
+	}
+	bounds := [2]string{}
+	for i, b := range []any{boundary.Min, boundary.Max} {
+		bound, ok := b.(*Expression)
+		if !ok {
+			return "", fmt.Errorf("unable to serialize range bound of type %T to lucene", b)
+		}
+		if bounds[i], err = luceneValue(bound); err != nil {
+			return "", err
+		}
+	}
+	if boundary.Inclusive {
+		return fmt.Sprintf("%s:[%s TO %s]", field, bounds[0], bounds[1]), nil
+	}
+	return fmt.Sprintf("%s:{%s TO %s}", field, bounds[0], bounds[1]), nil
+}
+
+func luceneIn(e *Expression) (string, error) {
+	field, err := luceneField(e.Left)
+	if err != nil {
+		return "", err
+	}
+	list, ok := e.Right.(*Expression)
+	if !ok || list.Op != List {
+		return "", fmt.Errorf("in requires a list, got %v", e.Right)
+	}
+	items, ok := list.Left.([]*Expression)
+	if !ok || len(items) == 0 {
+		return "", fmt.Errorf("in requires a non empty list")
+	}
+	vals := []string{}
+	for _, item := range items {
+		v, err := luceneValue(item)
+		if err != nil {
+			return "", err
+		}
+		vals = append(vals, v)
+	}
+	return fmt.Sprintf("%s:(%s)", field, strings.Join(vals, " OR ")), nil
+}
+
+// luceneCompound serializes AND and OR. Both are left associative, so only a left child with
+// the same operator can go without parentheses.
+func luceneCompound(e *Expression) (string, error) {
+	left, err := luceneChild(e.Left, func(c *Expression) bool { return c.Op != e.Op && isCompound(c) })
+	if err != nil {
+		return "", err
+	}
+	right, err := luceneChild(e.Right, isCompound)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s %s %s", left, toString[e.Op], right), nil
+}
+
+func lucenePrefix(e *Expression, prefix string) (string, error) {
+	s, err := luceneChild(e.Left, func(c *Expression) bool { return !isTerm(c) })
+	if err != nil {
+		return "", err
+	}
+	return prefix + s, nil
+}
+
+func luceneSuffix(e *Expression, suffix string) (string, error) {
+	s, err := luceneChild(e.Left, func(c *Expression) bool { return !isTerm(c) })
+	if err != nil {
+		return "", err
+	}
+	return s + suffix, nil
+}
+
+// luceneChild serializes a sub expression, wrapping it in parentheses when wrap says so.
+func luceneChild(child any, wrap func(*Expression) bool) (string, error) {
+	c, ok := child.(*Expression)
+	if !ok || c == nil {
+		return "", fmt.Errorf("unable to serialize sub expression of type %T to lucene", child)
+	}
+	s, err := toLucene(c)
+	if err != nil {
+		return "", err
+	}
+	if wrap(c) {
+		return "(" + s + ")", nil
+	}
+	return s, nil
+}
+
+func isCompound(e *Expression) bool {
+	return e.Op == And || e.Op == Or
+}
+
+// isTerm reports whether the expression serializes as a single token that prefix and suffix
+// operators bind to without parentheses.
+func isTerm(e *Expression) bool {
+	switch e.Op {
+	case Literal, Wild, Regexp, Null, Equals, Like, Greater, GreaterEq, Less, LessEq, Range, In:
+		return true
+	}
+	return false
+}
+
+// luceneField serializes the field of a term. Column names are escaped so they lex as a single
+// word that isn't mistaken for a number, keyword or null.
+func luceneField(in any) (string, error) {
+	e, ok := in.(*Expression)
+	if !ok || e.Op != Literal {
+		return "", fmt.Errorf("unable to serialize field %v to lucene", in)
+	}
+	switch v := e.Left.(type) {
+	case Column:
+		return escapeField(string(v)), nil
+	case RawColumn:
+		return "", fmt.Errorf("unable to serialize raw column %s to lucene", string(v))
+	}
+	return luceneValue(e)
+}
+
+func escapeField(name string) string {
+	var b strings.Builder
+	if !isBareWord(name) && (strings.HasPrefix(name, "-") || strings.IndexFunc(name, isSpecialRune) < 0) {
+		// a leading escape stops numbers, keywords and a leading - from being lexed as such
+		b.WriteByte('\\')
+	}
+	for _, r := range name {
+		if isSpecialRune(r) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// luceneValue serializes a leaf expression.
+func luceneValue(e *Expression) (string, error) {
+	switch e.Op {
+	case Null:
+		return "null", nil
+	case Wild:
+		if prefix, ok := phrasePrefix(e); ok {
+			return quote(prefix) + "*", nil
+		}
+		return fmt.Sprint(e.Left), nil
+	case Regexp:
+		s := fmt.Sprint(e.Left)
+		if isRegexpLiteral(s) {
+			return s, nil
+		}
+		return "/" + strings.ReplaceAll(s, "/", `\/`) + "/", nil
+	case Literal:
+	default:
+		return "", fmt.Errorf("unable to serialize %s as a lucene value", e.Op)
+	}
+
+	switch v := e.Left.(type) {
+	case string:
+		if isBareWord(v) {
+			return v, nil
+		}
+		return quote(v), nil
+	case Column:
+		return escapeField(string(v)), nil
+	case int:
+		return strconv.Itoa(v), nil
+	case float64:
+		s := strconv.FormatFloat(v, 'f', -1, 64)
+		if !strings.Contains(s, ".") {
+			// keep the value a float when it's parsed again
+			s += ".0"
+		}
+		return s, nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case time.Time:
+		return quote(v.Format(time.RFC3339Nano)), nil
+	}
+	return "", fmt.Errorf("unable to serialize literal of type %T to lucene", e.Left)
+}
+
+func quote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// isBareWord reports whether s parses back to the same string without quoting.
+func isBareWord(s string) bool {
+	if s == "" || strings.HasPrefix(s, "-") {
+		return false
+	}
+	for _, r := range s {
+		if !isWordRune(r) {
+			return false
+		}
+	}
+	for _, keyword := range []string{"null", "and", "or", "not", "to"} {
+		if strings.EqualFold(s, keyword) {
+			return false
+		}
+	}
+	if _, err := strconv.Atoi(s); err == nil {
+		return false
+	}
+	if _, err := strconv.ParseFloat(s, 64); err == nil {
+		return false
+	}
+	return true
+}
+
+// isWordRune reports whether r can appear in a word without changing its meaning.
+func isWordRune(r rune) bool {
+	return r == '_' || r == '.' || r == '-' || r == '%' || r == '@' || r == '#' || r == '$' ||
+		unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func isSpecialRune(r rune) bool {
+	return !isWordRune(r)
+}
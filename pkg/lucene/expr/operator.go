@@ -32,6 +32,7 @@ const (
 	In
 	List
 	Null
+	Proximity
 )
 
 // String renders the operator as a string
@@ -60,6 +61,7 @@ var fromString = map[string]Operator{
 	"IN":         In,
 	"LIST":       List,
 	"NULL":       Null,
+	"PROXIMITY":  Proximity,
 }
 
 var toString = map[Operator]string{
@@ -83,4 +85,5 @@ var toString = map[Operator]string{
 	In:        "IN",
 	List:      "LIST",
 	Null:      "NULL",
+	Proximity: "PROXIMITY",
 }
@@ -28,6 +28,7 @@ var renderers = map[Operator]renderer{
 	In:        renderBasic,
 	List:      renderList,
 	Null:      renderNullLiteral,
+	Proximity: renderProximity,
 }
 
 func renderEquals(e *Expression, verbose bool) string {
@@ -97,6 +98,14 @@ func renderFuzzy(e *Expression, verbose bool) string {
 	return fmt.Sprintf("%s~", e.Left)
 }
 
+func renderProximity(e *Expression, verbose bool) string {
+	if verbose {
+		return fmt.Sprintf("%s(%#v~%d)", toString[e.Op], e.Left, e.slop)
+	}
+
+	return fmt.Sprintf("%s~%d", e.Left, e.slop)
+}
+
 func renderRange(e *Expression, verbose bool) string {
 	boundary := e.Right.(*RangeBoundary)
 	if verbose {
@@ -156,6 +165,10 @@ func renderLiteral(e *Expression, verbose bool) string {
 		escaped := strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s)
 		return fmt.Sprintf(`"%s"`, escaped)
 	}
+	// a phrase prefix keeps its * outside the quotes so it re-parses as one
+	if prefix, ok := phrasePrefix(e); ok {
+		return quote(prefix) + "*"
+	}
 	if isStr && strings.ContainsAny(s, " ") {
 		return fmt.Sprintf(`"%s"`, s)
 	}
@@ -0,0 +1,414 @@
+package expr
+
+import (
+	"reflect"
+	"time"
+)
+
+// Simplify returns a smaller expression that matches the same rows in the sql drivers. It
+// flattens nested ANDs and ORs and drops duplicate clauses, removes MUST wrappers and double
+// negations, drops a:[* TO *] from an AND (and reduces an OR with one to it, since the sql drivers
+// render it as 1=1), merges overlapping ranges and comparisons on the same field into one range
+// and collapses equalities on the same field in an OR into an IN, e.g. a:1 OR a:2 OR a:3 into
+// a:(1 OR 2 OR 3). Ranges are only merged when their bounds are numbers or timestamps, whose order
+// doesn't depend on the database's collation. The input expression is never modified.
+//
+// Simplify knows nothing about a driver's configuration, so it treats every field as a plain
+// column. Fields that a driver matches differently, such as array fields, related-table fields or
+// fields a non-sql driver searches, may not match the same rows afterwards.
+func Simplify(e *Expression) *Expression {
+	if e == nil {
+		return nil
+	}
+
+	switch e.Op {
+	case Must:
+		if inner, ok := e.Left.(*Expression); ok && inner != nil {
+			return Simplify(inner)
+		}
+	case Not, MustNot:
+		inner, ok := e.Left.(*Expression)
+		if !ok || inner == nil {
+			return e
+		}
+		inner = Simplify(inner)
+		if twice, ok := inner.Left.(*Expression); ok && (inner.Op == Not || inner.Op == MustNot) {
+			return twice
+		}
+		cp := *e
+		cp.Left = inner
+		return &cp
+	case And, Or:
+		return simplifyChain(e)
+	case Boost, Fuzzy, Proximity:
+		if inner, ok := e.Left.(*Expression); ok && inner != nil {
+			cp := *e
+			cp.Left = Simplify(inner)
+			return &cp
+		}
+	}
+	return e
+}
+
+// simplifyChain simplifies the clauses of a chain of ANDs or ORs as one list of clauses.
+func simplifyChain(e *Expression) *Expression {
+	clauses := []*Expression{}
+	for _, clause := range chainClauses(e, e.Op) {
+		clauses = append(clauses, chainClauses(Simplify(clause), e.Op)...)
+	}
+	clauses = dedupe(clauses)
+
+	if e.Op == Or {
+		for _, clause := range clauses {
+			if isMatchAll(clause) {
+				return clause
+			}
+		}
+		clauses = collapseEqualities(clauses)
+	} else {
+		matchAll := []*Expression{}
+		rest := []*Expression{}
+		for _, clause := range clauses {
+			if isMatchAll(clause) {
+				matchAll = append(matchAll, clause)
+				continue
+			}
+			rest = append(rest, clause)
+		}
+		if len(rest) == 0 {
+			return matchAll[0]
+		}
+		clauses = rest
+	}
+	clauses = dedupe(mergeRanges(clauses, e.Op))
+
+	out := clauses[0]
+	for _, clause := range clauses[1:] {
+		out = Expr(out, e.Op, clause)
+	}
+	return out
+}
+
+// chainClauses returns the clauses of a chain of the same operator, e.g. a, b and c for
+// a AND (b AND c).
+func chainClauses(e *Expression, op Operator) []*Expression {
+	left, isLeft := e.Left.(*Expression)
+	right, isRight := e.Right.(*Expression)
+	if e.Op != op || !isLeft || !isRight || left == nil || right == nil {
+		return []*Expression{e}
+	}
+	return append(chainClauses(left, op), chainClauses(right, op)...)
+}
+
+// dedupe drops the clauses that are identical to an earlier one.
+func dedupe(clauses []*Expression) []*Expression {
+	out := []*Expression{}
+	for _, clause := range clauses {
+		seen := false
+		for _, kept := range out {
+			if reflect.DeepEqual(kept, clause) {
+				seen = true
+				break
+			}
+		}
+		if !seen {
+			out = append(out, clause)
+		}
+	}
+	return out
+}
+
+// isMatchAll reports whether the clause is a range unbounded on both sides, e.g. a:[* TO *].
+func isMatchAll(e *Expression) bool {
+	if e.Op != Range {
+		return false
+	}
+	boundary, ok := e.Right.(*RangeBoundary)
+	return ok && isUnbounded(boundary.Min) && isUnbounded(boundary.Max)
+}
+
+func isUnbounded(bound any) bool {
+	e, ok := bound.(*Expression)
+	return ok && e != nil && e.Op == Wild && e.Left == "*"
+}
+
+// simplifyColumn returns the name of the column a comparison is on, when it is a plain column.
+func simplifyColumn(e *Expression) (Column, bool) {
+	left, ok := e.Left.(*Expression)
+	if !ok || left == nil || left.Op != Literal {
+		return "", false
+	}
+	column, ok := left.Left.(Column)
+	return column, ok
+}
+
+// collapseEqualities collapses the equalities and lists on the same column in an OR into a single
+// list, in the place of the first one.
+func collapseEqualities(clauses []*Expression) []*Expression {
+	type list struct {
+		index int
+		items []*Expression
+	}
+	lists := map[Column]*list{}
+	out := []*Expression{}
+	for _, clause := range clauses {
+		column, items, ok := equalityItems(clause)
+		if !ok {
+			out = append(out, clause)
+			continue
+		}
+		if l, found := lists[column]; found {
+			l.items = dedupe(append(l.items, items...))
+			out[l.index] = IN(Lit(column), LIST(l.items))
+			continue
+		}
+		lists[column] = &list{index: len(out), items: items}
+		out = append(out, clause)
+	}
+	return out
+}
+
+// equalityItems returns the column and values of an equality with a literal or null, or of a list.
+func equalityItems(e *Expression) (Column, []*Expression, bool) {
+	column, ok := simplifyColumn(e)
+	if !ok {
+		return "", nil, false
+	}
+	switch e.Op {
+	case Equals:
+		right, ok := e.Right.(*Expression)
+		if !ok || right == nil || (right.Op != Literal && right.Op != Null) {
+			return "", nil, false
+		}
+		return column, []*Expression{right}, true
+	case In:
+		list, ok := e.Right.(*Expression)
+		if !ok || list == nil || list.Op != List {
+			return "", nil, false
+		}
+		items, ok := list.Left.([]*Expression)
+		if !ok {
+			return "", nil, false
+		}
+		return column, append([]*Expression{}, items...), true
+	}
+	return "", nil, false
+}
+
+// interval is the values a range or comparison matches. A nil bound is unbounded.
+type interval struct {
+	min, max       any
+	minInc, maxInc bool
+}
+
+// clauseInterval returns the values a range or comparison with number or timestamp bounds matches.
+func clauseInterval(e *Expression) (Column, interval, bool) {
+	column, ok := simplifyColumn(e)
+	if !ok {
+		return "", interval{}, false
+	}
+
+	if e.Op == Range {
+		boundary, ok := e.Right.(*RangeBoundary)
+		if !ok {
+			return "", interval{}, false
+		}
+		in := interval{minInc: boundary.Inclusive, maxInc: boundary.Inclusive}
+		for _, b := range []struct {
+			bound any
+			value *any
+		}{{boundary.Min, &in.min}, {boundary.Max, &in.max}} {
+			if isUnbounded(b.bound) {
+				continue
+			}
+			v, ok := orderedValue(b.bound)
+			if !ok {
+				return "", interval{}, false
+			}
+			*b.value = v
+		}
+		return column, in, in.min != nil || in.max != nil
+	}
+
+	v, ok := orderedValue(e.Right)
+	if !ok {
+		return "", interval{}, false
+	}
+	switch e.Op {
+	case Greater, GreaterEq:
+		return column, interval{min: v, minInc: e.Op == GreaterEq}, true
+	case Less, LessEq:
+		return column, interval{max: v, maxInc: e.Op == LessEq}, true
+	}
+	return "", interval{}, false
+}
+
+// orderedValue returns the value of a number or timestamp literal.
+func orderedValue(in any) (any, bool) {
+	e, ok := in.(*Expression)
+	if !ok || e == nil || e.Op != Literal {
+		return nil, false
+	}
+	switch e.Left.(type) {
+	case int, float64, time.Time:
+		return e.Left, true
+	}
+	return nil, false
+}
+
+// compareValues compares two numbers or two timestamps, reporting false when they can't be.
+func compareValues(a, b any) (int, bool) {
+	if at, ok := a.(time.Time); ok {
+		bt, ok := b.(time.Time)
+		return at.Compare(bt), ok
+	}
+	af, aok := toFloat(a)
+	bf, bok := toFloat(b)
+	switch {
+	case !aok || !bok:
+		return 0, false
+	case af < bf:
+		return -1, true
+	case af > bf:
+		return 1, true
+	}
+	return 0, true
+}
+
+func toFloat(in any) (float64, bool) {
+	switch v := in.(type) {
+	case int:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
+
+// mergeRanges merges each range or comparison with an earlier one on the same column when the
+// values both match, for an AND, or either matches, for an OR, are a single range.
+func mergeRanges(clauses []*Expression, op Operator) []*Expression {
+	type merged struct {
+		index int
+		in    interval
+	}
+	ranges := map[Column]*merged{}
+	out := []*Expression{}
+	for _, clause := range clauses {
+		column, in, ok := clauseInterval(clause)
+		if !ok {
+			out = append(out, clause)
+			continue
+		}
+		if m, found := ranges[column]; found {
+			combined, ok := intersect(m.in, in)
+			if op == Or {
+				combined, ok = union(m.in, in)
+			}
+			if rendered, representable := intervalExpr(column, combined); ok && representable {
+				m.in = combined
+				out[m.index] = rendered
+				continue
+			}
+		}
+		ranges[column] = &merged{index: len(out), in: in}
+		out = append(out, clause)
+	}
+	return out
+}
+
+// intersect returns the values in both intervals, failing when there are none or they can't be
+// compared.
+func intersect(a, b interval) (interval, bool) {
+	out := a
+	if b.min != nil {
+		c, ok := compareValues(a.min, b.min)
+		switch {
+		case a.min == nil || (ok && c < 0):
+			out.min, out.minInc = b.min, b.minInc
+		case !ok:
+			return interval{}, false
+		case c == 0:
+			out.minInc = a.minInc && b.minInc
+		}
+	}
+	if b.max != nil {
+		c, ok := compareValues(a.max, b.max)
+		switch {
+		case a.max == nil || (ok && c > 0):
+			out.max, out.maxInc = b.max, b.maxInc
+		case !ok:
+			return interval{}, false
+		case c == 0:
+			out.maxInc = a.maxInc && b.maxInc
+		}
+	}
+	if out.min != nil && out.max != nil {
+		c, ok := compareValues(out.min, out.max)
+		if !ok || c > 0 || (c == 0 && !(out.minInc && out.maxInc)) {
+			return interval{}, false
+		}
+	}
+	return out, true
+}
+
+// union returns the values in either interval, failing when they neither overlap nor touch, or
+// when the union is unbounded on both sides, which unlike a:[* TO *] wouldn't match nulls.
+func union(a, b interval) (interval, bool) {
+	if !overlaps(a, b) || !overlaps(b, a) {
+		return interval{}, false
+	}
+	out := a
+	if a.min != nil {
+		c, ok := compareValues(a.min, b.min)
+		switch {
+		case b.min == nil || (ok && c > 0):
+			out.min, out.minInc = b.min, b.minInc
+		case !ok:
+			return interval{}, false
+		case c == 0:
+			out.minInc = a.minInc || b.minInc
+		}
+	}
+	if a.max != nil {
+		c, ok := compareValues(a.max, b.max)
+		switch {
+		case b.max == nil || (ok && c < 0):
+			out.max, out.maxInc = b.max, b.maxInc
+		case !ok:
+			return interval{}, false
+		case c == 0:
+			out.maxInc = a.maxInc || b.maxInc
+		}
+	}
+	return out, out.min != nil || out.max != nil
+}
+
+// overlaps reports whether a doesn't end before b starts.
+func overlaps(a, b interval) bool {
+	if a.max == nil || b.min == nil {
+		return true
+	}
+	c, ok := compareValues(a.max, b.min)
+	return ok && (c > 0 || (c == 0 && (a.maxInc || b.minInc)))
+}
+
+// intervalExpr returns the range or comparison matching the interval, failing when a range can't
+// match it because its bounds differ in inclusivity.
+func intervalExpr(column Column, in interval) (*Expression, bool) {
+	switch {
+	case in.min == nil && in.max == nil:
+		return nil, false
+	case in.max == nil && in.minInc:
+		return GREATEREQ(Lit(column), Lit(in.min)), true
+	case in.max == nil:
+		return GREATER(Lit(column), Lit(in.min)), true
+	case in.min == nil && in.maxInc:
+		return LESSEQ(Lit(column), Lit(in.max)), true
+	case in.min == nil:
+		return LESS(Lit(column), Lit(in.max)), true
+	case in.minInc != in.maxInc:
+		return nil, false
+	}
+	return Rang(Lit(column), Lit(in.min), Lit(in.max), in.minInc), true
+}
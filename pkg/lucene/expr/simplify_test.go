@@ -0,0 +1,120 @@
+package expr
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSimplify(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC) }
+
+	type tc struct {
+		input *Expression
+		want  *Expression
+	}
+
+	tcs := map[string]tc{
+		"flattens_and_dedupes": {
+			input: AND(Eq("a", "x"), AND(Eq("b", "y"), AND(Eq("a", "x"), Eq("c", "z")))),
+			want:  AND(AND(Eq("a", "x"), Eq("b", "y")), Eq("c", "z")),
+		},
+		"duplicate_or": {
+			input: OR(Eq("a", "x"), Eq("a", "x")),
+			want:  Eq("a", "x"),
+		},
+		"drops_must": {
+			input: AND(MUST(Eq("a", "x")), MUST(AND(Eq("b", "y"), Eq("c", "z")))),
+			want:  AND(AND(Eq("a", "x"), Eq("b", "y")), Eq("c", "z")),
+		},
+		"double_not": {
+			input: NOT(MUSTNOT(Eq("a", "x"))),
+			want:  Eq("a", "x"),
+		},
+		"single_not_kept": {
+			input: MUSTNOT(MUST(Eq("a", "x"))),
+			want:  MUSTNOT(Eq("a", "x")),
+		},
+		"match_all_dropped_from_and": {
+			input: AND(Rang("a", "*", "*", true), Eq("b", "y")),
+			want:  Eq("b", "y"),
+		},
+		"match_all_wins_or": {
+			input: OR(Eq("b", "y"), Rang("a", "*", "*", false)),
+			want:  Rang("a", "*", "*", false),
+		},
+		"equalities_become_in": {
+			input: OR(OR(Eq("a", 1), Eq("b", 2)), OR(Eq("a", 2), Eq("a", 3))),
+			want:  OR(IN("a", LIST(Lit(1), Lit(2), Lit(3))), Eq("b", 2)),
+		},
+		"equality_joins_list": {
+			input: OR(IN("a", LIST(Lit("x"), Lit("y"))), OR(Eq("a", "y"), Eq("a", NULL()))),
+			want:  IN("a", LIST(Lit("x"), Lit("y"), NULL())),
+		},
+		"wildcard_not_collapsed": {
+			input: OR(Eq("a", "x"), LIKE("a", "y*")),
+			want:  OR(Eq("a", "x"), LIKE("a", "y*")),
+		},
+		"equalities_in_and_kept": {
+			input: AND(Eq("a", 1), Eq("a", 2)),
+			want:  AND(Eq("a", 1), Eq("a", 2)),
+		},
+		"intersects_ranges": {
+			input: AND(Rang("a", 1, 10, true), AND(Eq("b", "y"), Rang("a", 5, 20, true))),
+			want:  AND(Rang("a", 5, 10, true), Eq("b", "y")),
+		},
+		"intersects_comparisons": {
+			input: AND(Expr("a", GreaterEq, 1), AND(Expr("a", LessEq, 9), Expr("a", Greater, 0))),
+			want:  Rang("a", 1, 9, true),
+		},
+		"intersection_with_mixed_bounds_kept": {
+			input: AND(Expr("a", Greater, 1), Expr("a", LessEq, 9)),
+			want:  AND(Expr("a", Greater, 1), Expr("a", LessEq, 9)),
+		},
+		"empty_intersection_kept": {
+			input: AND(Rang("a", 1, 2, true), Rang("a", 5, 6, true)),
+			want:  AND(Rang("a", 1, 2, true), Rang("a", 5, 6, true)),
+		},
+		"unions_overlapping_ranges": {
+			input: OR(Rang("a", 1, 5, true), Rang("a", 3, 8.5, true)),
+			want:  Rang("a", 1, 8.5, true),
+		},
+		"unions_touching_ranges": {
+			input: OR(Rang("a", 1, 5, true), Rang("a", 5, "*", true)),
+			want:  Expr("a", GreaterEq, 1),
+		},
+		"disjoint_union_kept": {
+			input: OR(Rang("a", 1, 5, false), Rang("a", 5, 9, false)),
+			want:  OR(Rang("a", 1, 5, false), Rang("a", 5, 9, false)),
+		},
+		"unbounded_union_kept": {
+			input: OR(Expr("a", Less, 5), Expr("a", GreaterEq, 5)),
+			want:  OR(Expr("a", Less, 5), Expr("a", GreaterEq, 5)),
+		},
+		"timestamp_ranges": {
+			input: AND(Rang("d", day(1), day(20), true), Expr("d", LessEq, day(10))),
+			want:  Rang("d", day(1), day(10), true),
+		},
+		"string_ranges_kept": {
+			input: AND(Rang("a", "a", "m", true), Rang("a", "c", "z", true)),
+			want:  AND(Rang("a", "a", "m", true), Rang("a", "c", "z", true)),
+		},
+		"simplifies_under_boost": {
+			input: BOOST(OR(Eq("a", "x"), Eq("a", "y")), 2),
+			want:  BOOST(IN("a", LIST(Lit("x"), Lit("y"))), 2),
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			before := tc.input.String()
+			got := Simplify(tc.input)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf(errTemplate, "simplified expression doesn't match", tc.want, got)
+			}
+			if after := tc.input.String(); after != before {
+				t.Fatalf("Simplify modified its input\nbefore %s\nafter  %s", before, after)
+			}
+		})
+	}
+}
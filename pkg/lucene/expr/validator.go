@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"time"
 )
 
 type validator = func(*Expression) (err error)
@@ -29,6 +30,7 @@ var validators = map[Operator]validator{
 	In:        validateIn,
 	List:      validateList,
 	Null:      validateNull,
+	Proximity: validateProximity,
 }
 
 func validateEquals(e *Expression) (err error) {
@@ -212,6 +214,26 @@ func validateFuzzy(e *Expression) (err error) {
 	return nil
 }
 
+func validateProximity(e *Expression) (err error) {
+	if e == nil {
+		return nil
+	}
+
+	if e.Left == nil {
+		return errors.New("PROXIMITY validation: sub expression must not be nil")
+	}
+
+	if e.Right != nil {
+		return errors.New("PROXIMITY validation: must not have two sub expressions")
+	}
+
+	if e.slop < 0 {
+		return fmt.Errorf("PROXIMITY validation: slop must not be negative, got %d", e.slop)
+	}
+
+	return nil
+}
+
 func validateLiteral(e *Expression) (err error) {
 	if e == nil {
 		return nil
@@ -388,14 +410,23 @@ func isLiteralExpr(in any) bool {
 }
 
 func isLiteral(in any) bool {
-	return isString(in) || isNum(in) || isBool(in) || isColumn(in)
+	return isString(in) || isNum(in) || isBool(in) || isColumn(in) || isTime(in)
 }
 
-func isColumn(in any) bool {
-	_, is := in.(Column)
+func isTime(in any) bool {
+	_, is := in.(time.Time)
 	return is
 }
 
+func isColumn(in any) bool {
+	switch in.(type) {
+	case Column, RawColumn:
+		return true
+	default:
+		return false
+	}
+}
+
 func isString(in any) bool {
 	_, is := in.(string)
 	return is
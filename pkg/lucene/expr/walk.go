@@ -0,0 +1,193 @@
+package expr
+
+import "errors"
+
+// WalkFunc is called for every expression Walk visits. path holds the ancestors of e from the
+// root down to its parent and is only valid for the duration of the call.
+type WalkFunc func(e *Expression, path []*Expression) error
+
+// SkipChildren can be returned by a pre-order WalkFunc to skip the children of the expression.
+// Walk doesn't treat it as an error.
+var SkipChildren = errors.New("skip children")
+
+// Walk traverses the expression depth first, calling pre before visiting an expression's
+// children and post after. Either may be nil. Children are the sub expressions of compound
+// operators, the term and value of comparisons, the items of a List and the bounds of a Range,
+// left to right, so e.g. the column of a:b is visited as LITERAL(COLUMN(a)). Walk stops at
+// the first error returned by pre or post and returns it.
+func Walk(e *Expression, pre, post WalkFunc) error {
+	return walk(e, nil, pre, post)
+}
+
+func walk(e *Expression, path []*Expression, pre, post WalkFunc) error {
+	if e == nil {
+		return nil
+	}
+
+	skip := false
+	if pre != nil {
+		err := pre(e, path)
+		switch {
+		case errors.Is(err, SkipChildren):
+			skip = true
+		case err != nil:
+			return err
+		}
+	}
+
+	if !skip {
+		childPath := append(path[:len(path):len(path)], e)
+		for _, child := range children(e) {
+			if err := walk(child, childPath, pre, post); err != nil {
+				return err
+			}
+		}
+	}
+
+	if post != nil {
+		if err := post(e, path); err != nil && !errors.Is(err, SkipChildren) {
+			return err
+		}
+	}
+	return nil
+}
+
+// children returns the sub expressions of e in the order Walk visits them.
+func children(e *Expression) []*Expression {
+	out := []*Expression{}
+	for _, side := range []any{e.Left, e.Right} {
+		switch v := side.(type) {
+		case *Expression:
+			out = append(out, v)
+		case []*Expression:
+			out = append(out, v...)
+		case *RangeBoundary:
+			if v == nil {
+				continue
+			}
+			for _, bound := range []any{v.Min, v.Max} {
+				if b, ok := bound.(*Expression); ok {
+					out = append(out, b)
+				}
+			}
+		}
+	}
+
+	// drop nil children so visitors never see them
+	filtered := out[:0]
+	for _, child := range out {
+		if child != nil {
+			filtered = append(filtered, child)
+		}
+	}
+	return filtered
+}
+
+// RewriteFunc is called for every expression Rewrite visits and returns what takes its place:
+// e itself to keep it, another expression to replace it, or nil to delete it. path holds the
+// ancestors of e from the root down to its parent.
+type RewriteFunc func(e *Expression, path []*Expression) (*Expression, error)
+
+// Rewrite returns a copy of the expression with pre applied to every expression before its
+// children are rewritten and post applied after. Either may be nil. A replacement returned by
+// pre isn't passed to pre again but its children are rewritten in turn, so a replacement that
+// contains the original expression must guard against expanding it forever. Deleting an
+// expression in pre skips its children. The input expression is never modified.
+//
+// Deleting a node removes it from its parent: one side of an AND or OR collapses the
+// operator into the other side, an item of a List is dropped, and a deleted Range bound
+// becomes unbounded (*). A parent left without a required child, such as a NOT without
+// its sub expression, a comparison without its term or an empty List, is deleted as well.
+func Rewrite(e *Expression, pre, post RewriteFunc) (*Expression, error) {
+	return rewrite(e, nil, pre, post)
+}
+
+func rewrite(e *Expression, path []*Expression, pre, post RewriteFunc) (out *Expression, err error) {
+	if e == nil {
+		return nil, nil
+	}
+
+	if pre != nil {
+		e, err = pre(e, path)
+		if err != nil || e == nil {
+			return nil, err
+		}
+	}
+
+	cp := *e
+	childPath := append(path[:len(path):len(path)], &cp)
+
+	left, leftDeleted, err := rewriteSide(e.Left, childPath, pre, post)
+	if err != nil {
+		return nil, err
+	}
+	right, rightDeleted, err := rewriteSide(e.Right, childPath, pre, post)
+	if err != nil {
+		return nil, err
+	}
+	cp.Left, cp.Right = left, right
+
+	switch {
+	case (cp.Op == And || cp.Op == Or) && leftDeleted && rightDeleted:
+		return nil, nil
+	case (cp.Op == And || cp.Op == Or) && leftDeleted:
+		return right.(*Expression), nil
+	case (cp.Op == And || cp.Op == Or) && rightDeleted:
+		return left.(*Expression), nil
+	case leftDeleted || rightDeleted:
+		return nil, nil
+	}
+
+	if post != nil {
+		return post(&cp, path)
+	}
+	return &cp, nil
+}
+
+// rewriteSide rewrites one side of an expression and reports whether it was deleted entirely.
+func rewriteSide(side any, path []*Expression, pre, post RewriteFunc) (any, bool, error) {
+	switch v := side.(type) {
+	case *Expression:
+		if v == nil {
+			return side, false, nil
+		}
+		out, err := rewrite(v, path, pre, post)
+		if err != nil || out == nil {
+			return nil, out == nil, err
+		}
+		return out, false, nil
+	case []*Expression:
+		items := []*Expression{}
+		for _, item := range v {
+			out, err := rewrite(item, path, pre, post)
+			if err != nil {
+				return nil, false, err
+			}
+			if out != nil {
+				items = append(items, out)
+			}
+		}
+		return items, len(items) == 0 && len(v) > 0, nil
+	case *RangeBoundary:
+		if v == nil {
+			return side, false, nil
+		}
+		boundary := *v
+		for _, bound := range []*any{&boundary.Min, &boundary.Max} {
+			b, ok := (*bound).(*Expression)
+			if !ok {
+				continue
+			}
+			out, err := rewrite(b, path, pre, post)
+			if err != nil {
+				return nil, false, err
+			}
+			if out == nil {
+				out = WILD("*")
+			}
+			*bound = out
+		}
+		return &boundary, false, nil
+	}
+	return side, false, nil
+}
@@ -0,0 +1,252 @@
+package expr
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWalk(t *testing.T) {
+	e := AND(
+		Eq(Lit(Column("a")), Lit("b")),
+		OR(
+			NOT(Rang(Lit(Column("c")), Lit(1), WILD("*"), true)),
+			IN(Lit(Column("d")), LIST(Lit("x"), Lit("y"))),
+		),
+	)
+
+	pre := []string{}
+	post := []string{}
+	itemDepth := -1
+	err := Walk(e,
+		func(e *Expression, path []*Expression) error {
+			pre = append(pre, e.Op.String())
+			if e.Left == "x" {
+				itemDepth = len(path)
+			}
+			return nil
+		},
+		func(e *Expression, path []*Expression) error {
+			post = append(post, e.Op.String())
+			return nil
+		},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error walking expression: %v", err)
+	}
+
+	wantPre := []string{
+		"AND", "EQUALS", "LITERAL", "LITERAL",
+		"OR", "NOT", "RANGE", "LITERAL", "LITERAL", "WILD",
+		"IN", "LITERAL", "LIST", "LITERAL", "LITERAL",
+	}
+	if !reflect.DeepEqual(pre, wantPre) {
+		t.Fatalf(errTemplate, "pre-order visits don't match", wantPre, pre)
+	}
+
+	wantPost := []string{
+		"LITERAL", "LITERAL", "EQUALS",
+		"LITERAL", "LITERAL", "WILD", "RANGE", "NOT",
+		"LITERAL", "LITERAL", "LITERAL", "LIST", "IN", "OR", "AND",
+	}
+	if !reflect.DeepEqual(post, wantPost) {
+		t.Fatalf(errTemplate, "post-order visits don't match", wantPost, post)
+	}
+
+	if itemDepth != 4 {
+		t.Fatalf("expected list item to have 4 ancestors, got %d", itemDepth)
+	}
+}
+
+func TestWalkPath(t *testing.T) {
+	e := AND(Eq(Lit(Column("a")), Lit("b")), NOT(Eq(Lit(Column("c")), Lit("d"))))
+
+	negated := []string{}
+	err := Walk(e, func(e *Expression, path []*Expression) error {
+		col, ok := e.Left.(Column)
+		if !ok {
+			return nil
+		}
+		for _, parent := range path {
+			if parent.Op == Not {
+				negated = append(negated, string(col))
+			}
+		}
+		return nil
+	}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error walking expression: %v", err)
+	}
+	if want := []string{"c"}; !reflect.DeepEqual(negated, want) {
+		t.Fatalf(errTemplate, "negated fields don't match", want, negated)
+	}
+}
+
+func TestWalkSkipAndStop(t *testing.T) {
+	e := AND(NOT(Eq(Lit(Column("a")), Lit("b"))), Eq(Lit(Column("c")), Lit("d")))
+
+	visited := []string{}
+	err := Walk(e, func(e *Expression, path []*Expression) error {
+		visited = append(visited, e.Op.String())
+		if e.Op == Not {
+			return SkipChildren
+		}
+		return nil
+	}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error walking expression: %v", err)
+	}
+	if want := []string{"AND", "NOT", "EQUALS", "LITERAL", "LITERAL"}; !reflect.DeepEqual(visited, want) {
+		t.Fatalf(errTemplate, "visits don't match", want, visited)
+	}
+
+	stop := errors.New("stop")
+	count := 0
+	err = Walk(e, nil, func(e *Expression, path []*Expression) error {
+		count++
+		return stop
+	})
+	if !errors.Is(err, stop) || count != 1 {
+		t.Fatalf("expected walk to stop after the first error, got %v after %d visits", err, count)
+	}
+}
+
+func TestRewrite(t *testing.T) {
+	renameField := func(from, to string) RewriteFunc {
+		return func(e *Expression, path []*Expression) (*Expression, error) {
+			if col, ok := e.Left.(Column); ok && string(col) == from {
+				return Lit(Column(to)), nil
+			}
+			return e, nil
+		}
+	}
+	deleteField := func(field string) RewriteFunc {
+		return func(e *Expression, path []*Expression) (*Expression, error) {
+			if col, ok := e.Left.(Column); ok && string(col) == field {
+				return nil, nil
+			}
+			return e, nil
+		}
+	}
+	deleteValue := func(value string) RewriteFunc {
+		return func(e *Expression, path []*Expression) (*Expression, error) {
+			if e.Op == Literal && e.Left == value {
+				return nil, nil
+			}
+			return e, nil
+		}
+	}
+
+	type tc struct {
+		input *Expression
+		pre   RewriteFunc
+		post  RewriteFunc
+		want  *Expression
+	}
+
+	tcs := map[string]tc{
+		"rename_field": {
+			input: AND(Eq(Lit(Column("a")), Lit("b")), Rang(Lit(Column("a")), Lit(1), Lit(2), true)),
+			post:  renameField("a", "z"),
+			want:  AND(Eq(Lit(Column("z")), Lit("b")), Rang(Lit(Column("z")), Lit(1), Lit(2), true)),
+		},
+		"delete_side_of_and": {
+			input: AND(Eq(Lit(Column("secret")), Lit("b")), Eq(Lit(Column("c")), Lit("d"))),
+			post:  deleteField("secret"),
+			want:  Eq(Lit(Column("c")), Lit("d")),
+		},
+		"delete_side_of_or": {
+			input: OR(Eq(Lit(Column("c")), Lit("d")), Eq(Lit(Column("secret")), Lit("b"))),
+			post:  deleteField("secret"),
+			want:  Eq(Lit(Column("c")), Lit("d")),
+		},
+		"delete_under_not_removes_not": {
+			input: AND(NOT(Eq(Lit(Column("secret")), Lit("b"))), Eq(Lit(Column("c")), Lit("d"))),
+			post:  deleteField("secret"),
+			want:  Eq(Lit(Column("c")), Lit("d")),
+		},
+		"delete_everything": {
+			input: AND(Eq(Lit(Column("secret")), Lit("b")), MUST(Eq(Lit(Column("secret")), Lit("d")))),
+			post:  deleteField("secret"),
+			want:  nil,
+		},
+		"delete_list_item": {
+			input: IN(Lit(Column("a")), LIST(Lit("x"), Lit("y"))),
+			post:  deleteValue("x"),
+			want:  IN(Lit(Column("a")), LIST(Lit("y"))),
+		},
+		"delete_whole_list": {
+			input: AND(IN(Lit(Column("a")), LIST(Lit("x"))), Eq(Lit(Column("c")), Lit("d"))),
+			post:  deleteValue("x"),
+			want:  Eq(Lit(Column("c")), Lit("d")),
+		},
+		"delete_range_bound_opens_it": {
+			input: Rang(Lit(Column("a")), Lit("x"), Lit("y"), true),
+			post:  deleteValue("x"),
+			want:  Rang(Lit(Column("a")), WILD("*"), Lit("y"), true),
+		},
+		"pre_order_delete_skips_children": {
+			input: AND(NOT(Eq(Lit(Column("a")), Lit("b"))), Eq(Lit(Column("c")), Lit("d"))),
+			pre: func(e *Expression, path []*Expression) (*Expression, error) {
+				if e.Op == Not {
+					return nil, nil
+				}
+				return e, nil
+			},
+			post: func(e *Expression, path []*Expression) (*Expression, error) {
+				if e.Op == Literal && e.Left == "b" {
+					t.Fatalf("children of a deleted expression should not be visited")
+				}
+				return e, nil
+			},
+			want: Eq(Lit(Column("c")), Lit("d")),
+		},
+		"pre_order_replacement_is_rewritten": {
+			input: Eq(Lit(Column("a")), Lit("b")),
+			pre: func(e *Expression, path []*Expression) (*Expression, error) {
+				// the replacement's children are visited too, so only expand the top level
+				if e.Op == Equals && len(path) == 0 {
+					return OR(e, Eq(Lit(Column("a")), Lit("B"))), nil
+				}
+				return e, nil
+			},
+			post: renameField("a", "z"),
+			want: OR(Eq(Lit(Column("z")), Lit("b")), Eq(Lit(Column("z")), Lit("B"))),
+		},
+		"keeps_boost_power": {
+			input: BOOST(Eq(Lit(Column("a")), Lit("b")), 2),
+			post:  renameField("a", "z"),
+			want:  BOOST(Eq(Lit(Column("z")), Lit("b")), 2),
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			before := tc.input.String()
+			got, err := Rewrite(tc.input, tc.pre, tc.post)
+			if err != nil {
+				t.Fatalf("unexpected error rewriting expression: %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf(errTemplate, "rewritten expression doesn't match", tc.want, got)
+			}
+			if after := tc.input.String(); after != before {
+				t.Fatalf("Rewrite modified its input\nbefore %s\nafter  %s", before, after)
+			}
+		})
+	}
+}
+
+func TestRewriteError(t *testing.T) {
+	e := AND(Eq(Lit(Column("a")), Lit("b")), Eq(Lit(Column("c")), Lit("d")))
+	_, err := Rewrite(e, nil, func(e *Expression, path []*Expression) (*Expression, error) {
+		if col, ok := e.Left.(Column); ok && col == "c" {
+			return nil, errors.New("field c is not allowed")
+		}
+		return e, nil
+	})
+	if err == nil || !strings.Contains(err.Error(), "field c is not allowed") {
+		t.Fatalf("expected rewrite error, got %v", err)
+	}
+}
@@ -11,9 +11,13 @@ import (
 // Reduce will reduce the elems and nonTerminals stacks using the available reducers and return
 // those slices modified to contain the reduced expressions. The elems will contain the reduced
 // expression the the nonTerminals will contain the modified stack of nonTerminals yet to be reduced.
-func Reduce(elems []any, nonTerminals []lex.Token, defaultField string) ([]any, []lex.Token, bool) {
+// quoted reports whether a literal was lexed from a quoted phrase, which makes ~ on it a proximity
+// match rather than a fuzzy term, even for a single word such as "fox"~2. It may be nil when no
+// literal is quoted.
+func Reduce(elems []any, nonTerminals []lex.Token, defaultField string, quoted func(*expr.Expression) bool) ([]any, []lex.Token, bool) {
+	st := state{defaultField: defaultField, quoted: quoted}
 	for _, reducer := range reducers {
-		elems, nonTerminals, reduced := reducer(elems, nonTerminals, defaultField)
+		elems, nonTerminals, reduced := reducer(elems, nonTerminals, st)
 		if reduced {
 			return elems, nonTerminals, true
 		}
@@ -21,7 +25,25 @@ func Reduce(elems []any, nonTerminals []lex.Token, defaultField string) ([]any,
 	return elems, nonTerminals, false
 }
 
-type reducer func(elems []any, nonTerminals []lex.Token, defaultField string) ([]any, []lex.Token, bool)
+type reducer func(elems []any, nonTerminals []lex.Token, st state) ([]any, []lex.Token, bool)
+
+// state is what the reducers know about the query besides the stacks they reduce.
+type state struct {
+	defaultField string
+	quoted       func(*expr.Expression) bool
+}
+
+// isQuoted reports whether the literal, or the value of a field:literal term, was a quoted phrase.
+func (st state) isQuoted(e *expr.Expression) bool {
+	if e.Op == expr.Equals {
+		right, ok := e.Right.(*expr.Expression)
+		if !ok {
+			return false
+		}
+		e = right
+	}
+	return e.Op == expr.Literal && st.quoted != nil && st.quoted(e)
+}
 
 // reducers are the reducers that will be executed during the grammar parsing
 var reducers = []reducer{
@@ -39,7 +61,7 @@ var reducers = []reducer{
 	rangeop,
 }
 
-func equal(elems []any, nonTerminals []lex.Token, defaultField string) ([]any, []lex.Token, bool) {
+func equal(elems []any, nonTerminals []lex.Token, st state) ([]any, []lex.Token, bool) {
 	if len(elems) != 3 {
 		return elems, nonTerminals, false
 	}
@@ -106,7 +128,7 @@ func isChainedOrLiterals(in *expr.Expression) (out []*expr.Expression, ok bool)
 	return out, false
 }
 
-func compare(elems []any, nonTerminals []lex.Token, defaultField string) ([]any, []lex.Token, bool) {
+func compare(elems []any, nonTerminals []lex.Token, st state) ([]any, []lex.Token, bool) {
 	if len(elems) != 4 {
 		return elems, nonTerminals, false
 	}
@@ -152,7 +174,7 @@ func compare(elems []any, nonTerminals []lex.Token, defaultField string) ([]any,
 	return elems, drop(nonTerminals, 2), true
 }
 
-func compareEq(elems []any, nonTerminals []lex.Token, defaultField string) ([]any, []lex.Token, bool) {
+func compareEq(elems []any, nonTerminals []lex.Token, st state) ([]any, []lex.Token, bool) {
 	if len(elems) != 5 {
 		return elems, nonTerminals, false
 	}
@@ -205,7 +227,7 @@ func compareEq(elems []any, nonTerminals []lex.Token, defaultField string) ([]an
 
 }
 
-func and(elems []any, nonTerminals []lex.Token, defaultField string) ([]any, []lex.Token, bool) {
+func and(elems []any, nonTerminals []lex.Token, st state) ([]any, []lex.Token, bool) {
 	// if we don't have 3 items in the buffer it's not an AND clause
 	if len(elems) != 3 {
 		return elems, nonTerminals, false
@@ -230,15 +252,15 @@ func and(elems []any, nonTerminals []lex.Token, defaultField string) ([]any, []l
 	// we have a valid AND clause. Replace it in the stack
 	elems = []any{
 		expr.AND(
-			wrapLiteral(left, defaultField),
-			wrapLiteral(right, defaultField),
+			wrapLiteral(left, st.defaultField),
+			wrapLiteral(right, st.defaultField),
 		),
 	}
 	// we consumed one terminal, the AND
 	return elems, drop(nonTerminals, 1), true
 }
 
-func or(elems []any, nonTerminals []lex.Token, defaultField string) ([]any, []lex.Token, bool) {
+func or(elems []any, nonTerminals []lex.Token, st state) ([]any, []lex.Token, bool) {
 	// if we don't have 3 items in the buffer it's not an OR clause
 	if len(elems) != 3 {
 		return elems, nonTerminals, false
@@ -263,15 +285,15 @@ func or(elems []any, nonTerminals []lex.Token, defaultField string) ([]any, []le
 	// we have a valid OR clause. Replace it in the stack
 	elems = []any{
 		expr.OR(
-			wrapLiteral(left, defaultField),
-			wrapLiteral(right, defaultField),
+			wrapLiteral(left, st.defaultField),
+			wrapLiteral(right, st.defaultField),
 		),
 	}
 	// we consumed one terminal, the OR
 	return elems, drop(nonTerminals, 1), true
 }
 
-func not(elems []any, nonTerminals []lex.Token, defaultField string) ([]any, []lex.Token, bool) {
+func not(elems []any, nonTerminals []lex.Token, st state) ([]any, []lex.Token, bool) {
 	if len(elems) < 2 {
 		return elems, nonTerminals, false
 	}
@@ -291,14 +313,14 @@ func not(elems []any, nonTerminals []lex.Token, defaultField string) ([]any, []l
 	elems = elems[:len(elems)-2]
 	elems = append(elems,
 		expr.NOT(
-			wrapLiteral(negated, defaultField),
+			wrapLiteral(negated, st.defaultField),
 		),
 	)
 	// we consumed one terminal, the NOT
 	return elems, drop(nonTerminals, 1), true
 }
 
-func sub(elems []any, nonTerminals []lex.Token, defaultField string) ([]any, []lex.Token, bool) {
+func sub(elems []any, nonTerminals []lex.Token, st state) ([]any, []lex.Token, bool) {
 	// all the internal terms should have reduced by the time we hit this reducer
 	if len(elems) != 3 {
 		return elems, nonTerminals, false
@@ -318,7 +340,7 @@ func sub(elems []any, nonTerminals []lex.Token, defaultField string) ([]any, []l
 	return []any{elems[1]}, drop(nonTerminals, 2), true
 }
 
-func must(elems []any, nonTerminals []lex.Token, defaultField string) ([]any, []lex.Token, bool) {
+func must(elems []any, nonTerminals []lex.Token, st state) ([]any, []lex.Token, bool) {
 	if len(elems) != 2 {
 		return elems, nonTerminals, false
 	}
@@ -334,10 +356,10 @@ func must(elems []any, nonTerminals []lex.Token, defaultField string) ([]any, []
 	}
 
 	// we consumed 1 terminal, the +
-	return []any{expr.MUST(wrapLiteral(rest, defaultField))}, drop(nonTerminals, 1), true
+	return []any{expr.MUST(wrapLiteral(rest, st.defaultField))}, drop(nonTerminals, 1), true
 }
 
-func mustNot(elems []any, nonTerminals []lex.Token, defaultField string) ([]any, []lex.Token, bool) {
+func mustNot(elems []any, nonTerminals []lex.Token, st state) ([]any, []lex.Token, bool) {
 	if len(elems) != 2 {
 		return elems, nonTerminals, false
 	}
@@ -352,10 +374,10 @@ func mustNot(elems []any, nonTerminals []lex.Token, defaultField string) ([]any,
 		return elems, nonTerminals, false
 	}
 	// we consumed one terminal, the -
-	return []any{expr.MUSTNOT(wrapLiteral(rest, defaultField))}, drop(nonTerminals, 1), true
+	return []any{expr.MUSTNOT(wrapLiteral(rest, st.defaultField))}, drop(nonTerminals, 1), true
 }
 
-func fuzzy(elems []any, nonTerminals []lex.Token, defaultField string) ([]any, []lex.Token, bool) {
+func fuzzy(elems []any, nonTerminals []lex.Token, st state) ([]any, []lex.Token, bool) {
 	if len(elems) < 2 {
 		return elems, nonTerminals, false
 	}
@@ -370,31 +392,47 @@ func fuzzy(elems []any, nonTerminals []lex.Token, defaultField string) ([]any, [
 		return elems, nonTerminals, false
 	}
 
-	// If we have exactly 2 elements, use implicit distance of 1
+	// If we have exactly 2 elements, use the implicit distance
 	if len(elems) == 2 {
-		return []any{expr.FUZZY(rest, 1)}, drop(nonTerminals, 1), true
+		return []any{approximate(wrapLiteral(rest, st.defaultField), st.isQuoted(rest))}, drop(nonTerminals, 1), true
 	}
 
 	// We have 3+ elements. Check if elems[2] is a valid numeric distance
 	if distance, ok := elems[2].(*expr.Expression); ok {
 		if idistance, err := strconv.Atoi(distance.String()); err == nil {
-			return []any{expr.FUZZY(rest, idistance)}, drop(nonTerminals, 1), true
+			return []any{approximate(wrapLiteral(rest, st.defaultField), st.isQuoted(rest), idistance)}, drop(nonTerminals, 1), true
 		}
 		// elems[2] is an Expression but not a valid numeric distance
 		// This means we have [expr, ~, non-numeric-expr] which should be reduced
 		// to [FUZZY(expr, 1), non-numeric-expr] so the parser can inject an implicit AND
-		result := append([]any{expr.FUZZY(rest, 1)}, elems[2:]...)
+		result := append([]any{approximate(wrapLiteral(rest, st.defaultField), st.isQuoted(rest))}, elems[2:]...)
 		return result, drop(nonTerminals, 1), true
 	}
 
 	// elems[2] is NOT an Expression (might be a Token or something else)
 	// This means we have [expr, ~, token/other] - reduce just [expr, ~] with implicit distance
 	// The token/other will be handled in the next reduce cycle
-	result := append([]any{expr.FUZZY(rest, 1)}, elems[2:]...)
+	result := append([]any{approximate(wrapLiteral(rest, st.defaultField), st.isQuoted(rest))}, elems[2:]...)
 	return result, drop(nonTerminals, 1), true
 }
 
-func boost(elems []any, nonTerminals []lex.Token, defaultField string) ([]any, []lex.Token, bool) {
+// approximate makes the expression a fuzzy term, or a proximity match when it is a quoted phrase
+// (e.g. a:"quick fox"~5). Without a distance a term gets an edit distance of 1 and a phrase a
+// slop of 0, as in Lucene.
+func approximate(e *expr.Expression, phrase bool, distance ...int) *expr.Expression {
+	if !phrase {
+		if len(distance) == 0 {
+			return expr.FUZZY(e, 1)
+		}
+		return expr.FUZZY(e, distance[0])
+	}
+	if len(distance) == 0 {
+		return expr.PROXIMITY(e, 0)
+	}
+	return expr.PROXIMITY(e, distance[0])
+}
+
+func boost(elems []any, nonTerminals []lex.Token, st state) ([]any, []lex.Token, bool) {
 	if len(elems) < 2 {
 		return elems, nonTerminals, false
 	}
@@ -427,7 +465,7 @@ func boost(elems []any, nonTerminals []lex.Token, defaultField string) ([]any, [
 	return result, drop(nonTerminals, 1), true
 }
 
-func rangeop(elems []any, nonTerminals []lex.Token, defaultField string) ([]any, []lex.Token, bool) {
+func rangeop(elems []any, nonTerminals []lex.Token, st state) ([]any, []lex.Token, bool) {
 	// we need a term, :, [, begin, TO, end, ] to have a range operator which is 7 elems
 	if len(elems) != 7 {
 		return elems, nonTerminals, false
@@ -496,8 +534,13 @@ func toPositiveFloat(in string) (f float64, err error) {
 // we need this because we want to support lucene expressions like a:b AND "c" which needs a default
 // field to compare "c" against to be valid.
 func wrapLiteral(lit *expr.Expression, field string) *expr.Expression {
-	if lit.Op == expr.Literal && field != "" {
+	if isBareValue(lit) && field != "" {
 		return expr.Eq(expr.Column(field), lit)
 	}
 	return lit
 }
+
+// isBareValue checks if the expression is a value without a field, e.g. foo, fo* or /fo+/
+func isBareValue(e *expr.Expression) bool {
+	return e.Op == expr.Literal || e.Op == expr.Wild || e.Op == expr.Regexp
+}
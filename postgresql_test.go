@@ -72,11 +72,11 @@ func TestPostgresSQLEndToEnd(t *testing.T) {
 		},
 		"basic_fuzzy": {
 			input: "b AND a~",
-			err:   "unable to render operator [FUZZY]",
+			err:   "fuzzy term a requires a field",
 		},
 		"fuzzy_power": {
 			input: "b AND a~10",
-			err:   "unable to render operator [FUZZY]",
+			err:   "fuzzy term a requires a field",
 		},
 		"basic_boost": {
 			input: "b AND a^",
@@ -98,6 +98,10 @@ func TestPostgresSQLEndToEnd(t *testing.T) {
 			input: `url:/example.com\/foo\/bar\/.*/`,
 			want:  `"url" ~ 'example.com\/foo\/bar\/.*'`,
 		},
+		"regexp_ignore_case": {
+			input: `a:/b+/i`,
+			want:  `"a" ~* 'b+'`,
+		},
 		"regexp_single_char_pattern": {
 			input: `field:/./`,
 			want:  `"field" ~ '.'`,
@@ -227,8 +231,9 @@ func TestPostgresSQLEndToEnd(t *testing.T) {
 			want:  `((("title" = 'foo') OR ("title" = 'bar')) AND (("body" = 'foo') OR ("body" = 'bar'))) OR ("k" = 'v')`,
 		},
 		"fuzzy_key_value": {
-			input: "a:b~2 AND foo",
-			err:   "unable to render operator [FUZZY]",
+			input:        "a:b~2 AND foo",
+			want:         `(levenshtein("a", 'b') <= 2) AND ("c" = 'foo')`,
+			defaultField: "c",
 		},
 		"precedence_works": {
 			input: "a:b AND c:d OR e:f OR h:i AND j:k",
@@ -555,11 +560,11 @@ func TestPostgresParameterizedSQLEndToEnd(t *testing.T) {
 		},
 		"basic_fuzzy": {
 			input: "b AND a~",
-			err:   "unable to render operator [FUZZY]",
+			err:   "fuzzy term a requires a field",
 		},
 		"fuzzy_power": {
 			input: "b AND a~10",
-			err:   "unable to render operator [FUZZY]",
+			err:   "fuzzy term a requires a field",
 		},
 		"basic_boost": {
 			input: "b AND a^",
@@ -744,8 +749,10 @@ func TestPostgresParameterizedSQLEndToEnd(t *testing.T) {
 			wantParams: []any{"foo", "bar", "foo", "bar", "v"},
 		},
 		"fuzzy_key_value": {
-			input: "a:b~2 AND foo",
-			err:   "unable to render operator [FUZZY]",
+			input:        "a:b~2 AND foo",
+			wantStr:      `(levenshtein("a", $1) <= 2) AND ("c" = $2)`,
+			wantParams:   []any{"b", "foo"},
+			defaultField: "c",
 		},
 		"precedence_works": {
 			input:      "a:b AND c:d OR e:f OR h:i AND j:k",
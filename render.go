@@ -3,9 +3,15 @@ package lucene
 import "github.com/grindlemire/go-lucene/pkg/driver"
 
 var (
-	postgres = driver.NewPostgresDriver()
-	sqlite   = driver.NewSQLiteDriver()
-	mysql    = driver.NewMySQLDriver()
+	postgres   = driver.NewPostgresDriver()
+	sqlite     = driver.NewSQLiteDriver()
+	mysql      = driver.NewMySQLDriver()
+	mssql      = driver.NewSQLServerDriver()
+	oracle     = driver.NewOracleDriver()
+	clickhouse = driver.NewClickHouseDriver()
+	bigquery   = driver.NewBigQueryDriver()
+	elastic    = driver.NewElasticsearchDriver()
+	mongo      = driver.NewMongoDriver()
 )
 
 // ToPostgres is a wrapper that will render the lucene expression string as a postgres sql filter string.
@@ -90,3 +96,180 @@ func ToMariaDB(in string, opts ...Opt) (string, error) {
 func ToParameterizedMariaDB(in string, opts ...Opt) (s string, params []any, err error) {
 	return ToParameterizedMySQL(in, opts...)
 }
+
+// ToSQLServer is a wrapper that will render the lucene expression string as a SQL Server sql filter string.
+// Regular expressions return an error; use driver.SQLServerDialect to render them with a regex function.
+func ToSQLServer(in string, opts ...Opt) (string, error) {
+	e, err := Parse(in, opts...)
+	if err != nil {
+		return "", err
+	}
+
+	return mssql.Render(e)
+}
+
+// ToParameterizedSQLServer is a wrapper that will render the lucene expression string as a SQL Server sql
+// filter string with parameters. The returned string will contain @p1, @p2, ... placeholders and the params
+// are in the same order.
+func ToParameterizedSQLServer(in string, opts ...Opt) (s string, params []any, err error) {
+	e, err := Parse(in, opts...)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return mssql.RenderParam(e)
+}
+
+// ToOracle is a wrapper that will render the lucene expression string as an Oracle sql filter string.
+func ToOracle(in string, opts ...Opt) (string, error) {
+	e, err := Parse(in, opts...)
+	if err != nil {
+		return "", err
+	}
+
+	return oracle.Render(e)
+}
+
+// ToParameterizedOracle is a wrapper that will render the lucene expression string as an Oracle sql filter
+// string with parameters. The returned string will contain :1, :2, ... positional binds and the params are
+// in the same order.
+func ToParameterizedOracle(in string, opts ...Opt) (s string, params []any, err error) {
+	e, err := Parse(in, opts...)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return oracle.RenderParam(e)
+}
+
+// ToClickHouse is a wrapper that will render the lucene expression string as a ClickHouse sql filter string.
+func ToClickHouse(in string, opts ...Opt) (string, error) {
+	e, err := Parse(in, opts...)
+	if err != nil {
+		return "", err
+	}
+
+	return clickhouse.Render(e)
+}
+
+// ToParameterizedClickHouse is a wrapper that will render the lucene expression string as a ClickHouse sql
+// filter string with parameters. The returned string will contain typed {p1:String}, {p2:Int64}, ... query
+// parameters and params[i] holds the value of p<i+1>, e.g. to send as param_p1 over the HTTP interface.
+func ToParameterizedClickHouse(in string, opts ...Opt) (s string, params []any, err error) {
+	e, err := Parse(in, opts...)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return clickhouse.RenderParam(e)
+}
+
+// ToBigQuery is a wrapper that will render the lucene expression string as a BigQuery sql filter string.
+func ToBigQuery(in string, opts ...Opt) (string, error) {
+	e, err := Parse(in, opts...)
+	if err != nil {
+		return "", err
+	}
+
+	return bigquery.Render(e)
+}
+
+// ToParameterizedBigQuery is a wrapper that will render the lucene expression string as a BigQuery sql filter
+// string with named parameters. The returned string will contain @p1, @p2, ... placeholders and params holds
+// their names and values, ready for the query's parameter list.
+func ToParameterizedBigQuery(in string, opts ...Opt) (s string, params []driver.BigQueryParam, err error) {
+	e, err := Parse(in, opts...)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return bigquery.RenderQueryParams(e)
+}
+
+// ToElasticsearch is a wrapper that will render the lucene expression string as an Elasticsearch query DSL
+// search body of the form {"query": {...}}. The output also works with OpenSearch.
+func ToElasticsearch(in string, opts ...Opt) (string, error) {
+	e, err := Parse(in, opts...)
+	if err != nil {
+		return "", err
+	}
+
+	return elastic.Render(e)
+}
+
+// ToMongo is a wrapper that will render the lucene expression string as a MongoDB query filter. The filter
+// only holds standard library types and can be passed to the mongo driver as a bson.M.
+func ToMongo(in string, opts ...Opt) (map[string]any, error) {
+	e, err := Parse(in, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return mongo.RenderFilter(e)
+}
+
+// ToScoredPostgres renders the lucene expression string as a postgres sql filter string along with a relevance
+// score expression built from its boosts, meant for "WHERE <where> ORDER BY <score> DESC".
+func ToScoredPostgres(in string, opts ...Opt) (where string, score string, err error) {
+	e, err := Parse(in, opts...)
+	if err != nil {
+		return "", "", err
+	}
+
+	return postgres.RenderScored(e)
+}
+
+// ToParameterizedScoredPostgres is the parameterized form of ToScoredPostgres. The score's $N placeholders
+// continue from the filter's and params holds the values for both, filter first.
+func ToParameterizedScoredPostgres(in string, opts ...Opt) (where string, score string, params []any, err error) {
+	e, err := Parse(in, opts...)
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	return postgres.RenderScoredParam(e)
+}
+
+// ToScoredSQLite renders the lucene expression string as a SQLite sql filter string along with a relevance
+// score expression built from its boosts, meant for "WHERE <where> ORDER BY <score> DESC".
+func ToScoredSQLite(in string, opts ...Opt) (where string, score string, err error) {
+	e, err := Parse(in, opts...)
+	if err != nil {
+		return "", "", err
+	}
+
+	return sqlite.RenderScored(e)
+}
+
+// ToParameterizedScoredSQLite is the parameterized form of ToScoredSQLite. params holds the values for the
+// filter followed by the values for the score.
+func ToParameterizedScoredSQLite(in string, opts ...Opt) (where string, score string, params []any, err error) {
+	e, err := Parse(in, opts...)
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	return sqlite.RenderScoredParam(e)
+}
+
+// ToScoredMySQL renders the lucene expression string as a MySQL sql filter string along with a relevance
+// score expression built from its boosts, meant for "WHERE <where> ORDER BY <score> DESC".
+func ToScoredMySQL(in string, opts ...Opt) (where string, score string, err error) {
+	e, err := Parse(in, opts...)
+	if err != nil {
+		return "", "", err
+	}
+
+	return mysql.RenderScored(e)
+}
+
+// ToParameterizedScoredMySQL is the parameterized form of ToScoredMySQL. params holds the values for the
+// filter followed by the values for the score.
+func ToParameterizedScoredMySQL(in string, opts ...Opt) (where string, score string, params []any, err error) {
+	e, err := Parse(in, opts...)
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	return mysql.RenderScoredParam(e)
+}
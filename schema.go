@@ -0,0 +1,307 @@
+package lucene
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grindlemire/go-lucene/pkg/lucene/expr"
+)
+
+type fieldKind int
+
+const (
+	kindString fieldKind = iota
+	kindInt
+	kindFloat
+	kindBool
+	kindTimestamp
+	kindUUID
+	kindEnum
+)
+
+// FieldType is the declared type of a field in a Schema.
+type FieldType struct {
+	kind   fieldKind
+	values []string
+}
+
+// the field types that can be declared in a Schema
+var (
+	StringField    = FieldType{kind: kindString}
+	IntField       = FieldType{kind: kindInt}
+	FloatField     = FieldType{kind: kindFloat}
+	BoolField      = FieldType{kind: kindBool}
+	TimestampField = FieldType{kind: kindTimestamp}
+	UUIDField      = FieldType{kind: kindUUID}
+)
+
+// EnumField declares a string field that only accepts the given values.
+func EnumField(values ...string) FieldType {
+	return FieldType{kind: kindEnum, values: values}
+}
+
+func (t FieldType) String() string {
+	switch t.kind {
+	case kindInt:
+		return "int"
+	case kindFloat:
+		return "float"
+	case kindBool:
+		return "bool"
+	case kindTimestamp:
+		return "timestamp"
+	case kindUUID:
+		return "uuid"
+	case kindEnum:
+		return fmt.Sprintf("enum(%s)", strings.Join(t.values, ", "))
+	}
+	return "string"
+}
+
+// Schema declares the type of each field. Fields that are not in the schema keep the
+// default behavior of guessing the type from the literal.
+type Schema map[string]FieldType
+
+// WithSchema coerces the values compared against each field in the schema to the field's
+// declared type. A value that can't be converted causes Parse to fail with a *FieldTypeError.
+//
+// For example with {"zip": StringField} zip:02134 compares against the string "02134"
+// rather than the int 2134, and with {"created": TimestampField} the bounds of
+// created:[2024-01-01 TO *] become time.Time values. Timestamps are parsed as RFC 3339 or
+// as a plain 2006-01-02 date. Since : is the field separator, timestamps with a time of
+// day must be quoted, e.g. created:>"2024-01-01T10:00:00Z".
+func WithSchema(schema Schema) Opt {
+	return func(p *parser) {
+		if p.schema == nil {
+			p.schema = Schema{}
+		}
+		for field, typ := range schema {
+			p.schema[field] = typ
+		}
+	}
+}
+
+// FieldTypeError is returned when a value in a query can't be converted to the type declared
+// for its field with WithSchema.
+type FieldTypeError struct {
+	Field string
+	Type  FieldType
+	Value string
+}
+
+func (e *FieldTypeError) Error() string {
+	return fmt.Sprintf("field %q expects %s, got %q", e.Field, e.Type, e.Value)
+}
+
+// applySchema walks the parsed expression and coerces every value compared against a field
+// declared in the schema. Range bounds and comparisons against fields that aren't in the
+// schema are resolved to time.Time when they are date literals or date math.
+func (p *parser) applySchema(in any) error {
+	e, ok := in.(*expr.Expression)
+	if !ok || e == nil {
+		return nil
+	}
+
+	switch e.Op {
+	case expr.Equals, expr.Like, expr.Greater, expr.GreaterEq, expr.Less, expr.LessEq, expr.In, expr.Range:
+		return p.coerceField(e)
+	}
+
+	if err := p.applySchema(e.Left); err != nil {
+		return err
+	}
+	return p.applySchema(e.Right)
+}
+
+// boundValue is a value compared against a field along with whether date rounding should
+// round it up.
+type boundValue struct {
+	v       *expr.Expression
+	roundUp bool
+}
+
+func (p *parser) coerceField(e *expr.Expression) error {
+	term, ok := e.Left.(*expr.Expression)
+	if !ok {
+		return nil
+	}
+	col, ok := term.Left.(expr.Column)
+	if !ok {
+		return nil
+	}
+
+	values := []boundValue{}
+	switch right := e.Right.(type) {
+	case *expr.Expression:
+		var err error
+		values, err = p.groupValues(right, e.Op == expr.Greater || e.Op == expr.LessEq, values)
+		if err != nil {
+			return err
+		}
+	case *expr.RangeBoundary:
+		if b, ok := right.Min.(*expr.Expression); ok {
+			values = append(values, boundValue{v: b, roundUp: !right.Inclusive})
+		}
+		if b, ok := right.Max.(*expr.Expression); ok {
+			values = append(values, boundValue{v: b, roundUp: right.Inclusive})
+		}
+	}
+
+	typ, typed := p.schema[string(col)]
+	if !typed {
+		if e.Op == expr.Range || isComparison(e.Op) {
+			return p.resolveDates(string(col), values)
+		}
+		return nil
+	}
+
+	for _, v := range values {
+		if err := p.coerceValue(string(col), typ, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// groupValues collects the values compared against a field, including the ones in a group such
+// as a:(1 OR NOT 2). Comparisons nested in a group, e.g. x:1 in a:(x:1 OR b), are against their
+// own field and get the schema applied on their own.
+func (p *parser) groupValues(v *expr.Expression, roundUp bool, values []boundValue) ([]boundValue, error) {
+	if v == nil {
+		return values, nil
+	}
+
+	switch v.Op {
+	case expr.Literal, expr.Wild, expr.Regexp, expr.Null:
+		return append(values, boundValue{v: v, roundUp: roundUp}), nil
+	case expr.List:
+		items, _ := v.Left.([]*expr.Expression)
+		for _, item := range items {
+			var err error
+			values, err = p.groupValues(item, roundUp, values)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return values, nil
+	case expr.Equals, expr.Like, expr.Greater, expr.GreaterEq, expr.Less, expr.LessEq, expr.In, expr.Range:
+		return values, p.coerceField(v)
+	}
+
+	for _, side := range []any{v.Left, v.Right} {
+		child, ok := side.(*expr.Expression)
+		if !ok {
+			continue
+		}
+		var err error
+		values, err = p.groupValues(child, roundUp, values)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
+
+func isComparison(op expr.Operator) bool {
+	return op == expr.Greater || op == expr.GreaterEq || op == expr.Less || op == expr.LessEq
+}
+
+// resolveDates replaces the string values that are date literals or date math with the
+// time they resolve to and leaves everything else untouched. A value written like a date
+// that doesn't resolve, e.g. 2024-13-45, fails with a FieldTypeError as it would on a
+// TimestampField.
+func (p *parser) resolveDates(field string, values []boundValue) error {
+	for _, b := range values {
+		text, ok := b.v.Left.(string)
+		if b.v.Op != expr.Literal || !ok || !looksLikeDate(text) {
+			continue
+		}
+		t, err := p.parseDate(text, b.roundUp)
+		if err != nil {
+			return &FieldTypeError{Field: field, Type: TimestampField, Value: text}
+		}
+		b.v.Left = t
+	}
+	return nil
+}
+
+func (p *parser) coerceValue(field string, typ FieldType, b boundValue) error {
+	v := b.v
+	switch v.Op {
+	case expr.Null:
+		return nil
+	case expr.Wild, expr.Regexp:
+		s := fmt.Sprintf("%v", v.Left)
+		// a bare * (field:* or an open range bound) is valid for every type, other
+		// patterns only make sense on text
+		if s == "*" || typ.kind == kindString || typ.kind == kindEnum || typ.kind == kindUUID {
+			return nil
+		}
+		return &FieldTypeError{Field: field, Type: typ, Value: s}
+	case expr.Literal:
+	default:
+		return nil
+	}
+
+	// prefer the text as typed in the query so e.g. 02134 isn't first read as an int
+	text, ok := p.literalText[v]
+	if !ok {
+		text = fmt.Sprintf("%v", v.Left)
+	}
+
+	var coerced any
+	var err error
+	if typ.kind == kindTimestamp {
+		coerced, err = p.parseDate(text, b.roundUp)
+	} else {
+		coerced, err = coerce(typ, text)
+	}
+	if err != nil {
+		return &FieldTypeError{Field: field, Type: typ, Value: text}
+	}
+	v.Left = coerced
+	return nil
+}
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02",
+}
+
+func coerce(typ FieldType, text string) (any, error) {
+	switch typ.kind {
+	case kindInt:
+		return strconv.Atoi(text)
+	case kindFloat:
+		return strconv.ParseFloat(text, 64)
+	case kindBool:
+		return strconv.ParseBool(text)
+	case kindUUID:
+		if !uuidPattern.MatchString(text) {
+			return nil, fmt.Errorf("invalid uuid %q", text)
+		}
+		return strings.ToLower(text), nil
+	case kindEnum:
+		if !slices.Contains(typ.values, text) {
+			return nil, fmt.Errorf("invalid enum value %q", text)
+		}
+	}
+	return text, nil
+}
+
+func parseTimestamp(text string) (time.Time, error) {
+	for _, layout := range timestampLayouts {
+		if t, err := time.Parse(layout, text); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid timestamp %q", text)
+}
@@ -0,0 +1,231 @@
+package lucene
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSchemaCoercion(t *testing.T) {
+	type tc struct {
+		input      string
+		wantStr    string
+		wantParams []any
+	}
+
+	schema := Schema{
+		"zip":     StringField,
+		"age":     IntField,
+		"price":   FloatField,
+		"active":  BoolField,
+		"created": TimestampField,
+		"id":      UUIDField,
+		"status":  EnumField("open", "closed"),
+	}
+
+	tcs := map[string]tc{
+		"string_keeps_leading_zero": {
+			input:      "zip:02134",
+			wantStr:    `"zip" = $1`,
+			wantParams: []any{"02134"},
+		},
+		"string_keeps_float_text": {
+			input:      "zip:1.50",
+			wantStr:    `"zip" = $1`,
+			wantParams: []any{"1.50"},
+		},
+		"string_in_or": {
+			input:      "zip:02134 OR zip:02135",
+			wantStr:    `("zip" = $1) OR ("zip" = $2)`,
+			wantParams: []any{"02134", "02135"},
+		},
+		"int": {
+			input:      "age:>=21",
+			wantStr:    `"age" >= $1`,
+			wantParams: []any{21},
+		},
+		"float_from_int_text": {
+			input:      "price:[1 TO 9.5]",
+			wantStr:    `"price" >= $1 AND "price" <= $2`,
+			wantParams: []any{1.0, 9.5},
+		},
+		"bool": {
+			input:      "active:true",
+			wantStr:    `"active" = $1`,
+			wantParams: []any{true},
+		},
+		"timestamp_range": {
+			input:      "created:[2024-01-01 TO *]",
+			wantStr:    `"created" >= $1`,
+			wantParams: []any{time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+		},
+		"timestamp_quoted_rfc3339": {
+			input:      `created:<"2024-01-01T10:30:00Z"`,
+			wantStr:    `"created" < $1`,
+			wantParams: []any{time.Date(2024, 1, 1, 10, 30, 0, 0, time.UTC)},
+		},
+		"uuid_is_lowercased": {
+			input:      "id:0B6F4C1A-3C0E-4F5A-9E3B-2D1C0A9B8E7F",
+			wantStr:    `"id" = $1`,
+			wantParams: []any{"0b6f4c1a-3c0e-4f5a-9e3b-2d1c0a9b8e7f"},
+		},
+		"enum": {
+			input:      "status:open",
+			wantStr:    `"status" = $1`,
+			wantParams: []any{"open"},
+		},
+		"null_is_allowed": {
+			input:      "age:null",
+			wantStr:    `"age" IS NULL`,
+			wantParams: nil,
+		},
+		"exists_is_allowed": {
+			input:      "age:*",
+			wantStr:    `"age" SIMILAR TO '%'`,
+			wantParams: nil,
+		},
+		"untyped_field_is_guessed": {
+			input:      "other:02134",
+			wantStr:    `"other" = $1`,
+			wantParams: []any{2134},
+		},
+		"default_field": {
+			input:      "02134",
+			wantStr:    `"zip" = $1`,
+			wantParams: []any{"02134"},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			got, params, err := ToParameterizedPostgres(tc.input, WithSchema(schema), WithDefaultField("zip"))
+			if err != nil {
+				t.Fatalf("unexpected error rendering expression: %v", err)
+			}
+			if got != tc.wantStr {
+				t.Fatalf("\nwant %s\ngot  %s", tc.wantStr, got)
+			}
+			if !reflect.DeepEqual(params, tc.wantParams) {
+				t.Fatalf("\nwant params %#v\ngot  params %#v", tc.wantParams, params)
+			}
+		})
+	}
+}
+
+func TestSchemaGroupedValues(t *testing.T) {
+	type tc struct {
+		input      string
+		wantStr    string
+		wantParams []any
+	}
+
+	schema := Schema{
+		"zip": StringField,
+		"age": IntField,
+	}
+
+	tcs := map[string]tc{
+		"and": {
+			input:      "age:(1 AND 2)",
+			wantStr:    `"age" = ($1 AND $2)`,
+			wantParams: []any{1, 2},
+		},
+		"negated": {
+			input:      "age:(NOT 5)",
+			wantStr:    `"age" = (NOT($1))`,
+			wantParams: []any{5},
+		},
+		"nested_field_keeps_its_type": {
+			input:      "zip:(age:1 OR 02134)",
+			wantStr:    `"zip" = (("age" = $1) OR $2)`,
+			wantParams: []any{1, "02134"},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			got, params, err := ToParameterizedPostgres(tc.input, WithSchema(schema))
+			if err != nil {
+				t.Fatalf("unexpected error rendering expression: %v", err)
+			}
+			if got != tc.wantStr {
+				t.Fatalf("\nwant %s\ngot  %s", tc.wantStr, got)
+			}
+			if !reflect.DeepEqual(params, tc.wantParams) {
+				t.Fatalf("\nwant params %#v\ngot  params %#v", tc.wantParams, params)
+			}
+		})
+	}
+}
+
+func TestSchemaTypeMismatch(t *testing.T) {
+	type tc struct {
+		input     string
+		wantField string
+		wantValue string
+	}
+
+	schema := Schema{
+		"age":     IntField,
+		"price":   FloatField,
+		"active":  BoolField,
+		"created": TimestampField,
+		"id":      UUIDField,
+		"status":  EnumField("open", "closed"),
+	}
+
+	tcs := map[string]tc{
+		"int":              {input: "age:abc", wantField: "age", wantValue: "abc"},
+		"int_from_float":   {input: "age:1.5", wantField: "age", wantValue: "1.5"},
+		"int_in_range":     {input: "age:[1 TO x]", wantField: "age", wantValue: "x"},
+		"int_in_list":      {input: "age:(1 OR x)", wantField: "age", wantValue: "x"},
+		"int_in_group":     {input: "age:(1 AND (2 OR x))", wantField: "age", wantValue: "x"},
+		"int_negated":      {input: "age:(NOT x)", wantField: "age", wantValue: "x"},
+		"nested_in_group":  {input: "zip:(age:x OR b)", wantField: "age", wantValue: "x"},
+		"int_wildcard":     {input: "age:1*", wantField: "age", wantValue: "1*"},
+		"float":            {input: "price:>cheap", wantField: "price", wantValue: "cheap"},
+		"bool":             {input: "active:maybe", wantField: "active", wantValue: "maybe"},
+		"timestamp":        {input: "created:yesterday", wantField: "created", wantValue: "yesterday"},
+		"uuid":             {input: "id:1234", wantField: "id", wantValue: "1234"},
+		"enum":             {input: "status:pending", wantField: "status", wantValue: "pending"},
+		"nested_in_clause": {input: "a:b AND NOT (c:d OR age:old)", wantField: "age", wantValue: "old"},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			_, err := Parse(tc.input, WithSchema(schema))
+			var typeErr *FieldTypeError
+			if !errors.As(err, &typeErr) {
+				t.Fatalf("expected a *FieldTypeError, got: %v", err)
+			}
+			if typeErr.Field != tc.wantField || typeErr.Value != tc.wantValue {
+				t.Fatalf("expected error for %s=%q, got %s=%q", tc.wantField, tc.wantValue, typeErr.Field, typeErr.Value)
+			}
+		})
+	}
+}
+
+func TestSchemaWithFieldMapping(t *testing.T) {
+	got, params, err := ToParameterizedMySQL("zip:02134",
+		WithSchema(Schema{"zip": StringField}),
+		WithFieldMapping(map[string]string{"zip": "addr.zip_code"}),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error rendering expression: %v", err)
+	}
+	if want := "addr.zip_code = ?"; got != want {
+		t.Fatalf("\nwant %s\ngot  %s", want, got)
+	}
+	if want := []any{"02134"}; !reflect.DeepEqual(params, want) {
+		t.Fatalf("\nwant params %#v\ngot  params %#v", want, params)
+	}
+
+	got, err = ToSQLite(`created:"2024-01-01T10:30:00Z"`, WithSchema(Schema{"created": TimestampField}))
+	if err != nil {
+		t.Fatalf("unexpected error rendering expression: %v", err)
+	}
+	if want := `"created" = '2024-01-01 10:30:00'`; got != want {
+		t.Fatalf("\nwant %s\ngot  %s", want, got)
+	}
+}
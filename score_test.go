@@ -0,0 +1,148 @@
+package lucene
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/grindlemire/go-lucene/pkg/driver"
+	"github.com/grindlemire/go-lucene/pkg/lucene/expr"
+)
+
+func TestScored(t *testing.T) {
+	type tc struct {
+		input      string
+		wantWhere  string
+		wantScore  string
+		wantParams []any
+	}
+
+	tcs := map[string]tc{
+		"boosted_or": {
+			input:      "title:go^2 OR body:go",
+			wantWhere:  `("title" = $1) OR ("body" = $2)`,
+			wantScore:  `CASE WHEN "title" = $3 THEN 2 ELSE 0 END + CASE WHEN "body" = $4 THEN 1 ELSE 0 END`,
+			wantParams: []any{"go", "go", "go", "go"},
+		},
+		"boosted_group_weights_each_clause": {
+			input:      "(a:b OR c:d)^3 AND e:f",
+			wantWhere:  `(("a" = $1) OR ("c" = $2)) AND ("e" = $3)`,
+			wantScore:  `CASE WHEN "a" = $4 THEN 3 ELSE 0 END + CASE WHEN "c" = $5 THEN 3 ELSE 0 END + CASE WHEN "e" = $6 THEN 1 ELSE 0 END`,
+			wantParams: []any{"b", "d", "f", "b", "d", "f"},
+		},
+		"nested_boosts_multiply": {
+			input:      "(a:b^2 OR c:d)^1.5",
+			wantWhere:  `("a" = $1) OR ("c" = $2)`,
+			wantScore:  `CASE WHEN "a" = $3 THEN 3 ELSE 0 END + CASE WHEN "c" = $4 THEN 1.5 ELSE 0 END`,
+			wantParams: []any{"b", "d", "b", "d"},
+		},
+		"negated_clauses_do_not_score": {
+			input:      "+a:b^4 -c:d NOT e:f",
+			wantWhere:  `(("a" = $1) AND (NOT("c" = $2))) AND (NOT("e" = $3))`,
+			wantScore:  `CASE WHEN "a" = $4 THEN 4 ELSE 0 END`,
+			wantParams: []any{"b", "d", "f", "b"},
+		},
+		"boosted_range": {
+			input:      "age:[1 TO 5]^2",
+			wantWhere:  `"age" >= $1 AND "age" <= $2`,
+			wantScore:  `CASE WHEN "age" >= $3 AND "age" <= $4 THEN 2 ELSE 0 END`,
+			wantParams: []any{1, 5, 1, 5},
+		},
+		"default_field": {
+			input:      "a:b^2 AND foo",
+			wantWhere:  `("a" = $1) AND ("x" = $2)`,
+			wantScore:  `CASE WHEN "a" = $3 THEN 2 ELSE 0 END + CASE WHEN "x" = $4 THEN 1 ELSE 0 END`,
+			wantParams: []any{"b", "foo", "b", "foo"},
+		},
+		"only_negations": {
+			input:      "NOT a:b",
+			wantWhere:  `NOT("a" = $1)`,
+			wantScore:  `0`,
+			wantParams: []any{"b"},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			where, score, params, err := ToParameterizedScoredPostgres(tc.input, WithDefaultField("x"))
+			if err != nil {
+				t.Fatalf("unexpected error rendering expression: %v", err)
+			}
+			if where != tc.wantWhere {
+				t.Fatalf("\nwant where %s\ngot  where %s", tc.wantWhere, where)
+			}
+			if score != tc.wantScore {
+				t.Fatalf("\nwant score %s\ngot  score %s", tc.wantScore, score)
+			}
+			if !reflect.DeepEqual(params, tc.wantParams) {
+				t.Fatalf("\nwant params %#v\ngot  params %#v", tc.wantParams, params)
+			}
+		})
+	}
+}
+
+func TestScoredDialects(t *testing.T) {
+	input := "title:go^2 OR body:go"
+
+	where, score, err := ToScoredMySQL(input)
+	if err != nil {
+		t.Fatalf("unexpected error rendering expression: %v", err)
+	}
+	if want := "(`title` = 'go') OR (`body` = 'go')"; where != want {
+		t.Fatalf("\nwant where %s\ngot  where %s", want, where)
+	}
+	if want := "CASE WHEN `title` = 'go' THEN 2 ELSE 0 END + CASE WHEN `body` = 'go' THEN 1 ELSE 0 END"; score != want {
+		t.Fatalf("\nwant score %s\ngot  score %s", want, score)
+	}
+
+	where, score, params, err := ToParameterizedScoredSQLite(input)
+	if err != nil {
+		t.Fatalf("unexpected error rendering expression: %v", err)
+	}
+	if want := `("title" = ?) OR ("body" = ?)`; where != want {
+		t.Fatalf("\nwant where %s\ngot  where %s", want, where)
+	}
+	if want := `CASE WHEN "title" = ? THEN 2 ELSE 0 END + CASE WHEN "body" = ? THEN 1 ELSE 0 END`; score != want {
+		t.Fatalf("\nwant score %s\ngot  score %s", want, score)
+	}
+	if want := []any{"go", "go", "go", "go"}; !reflect.DeepEqual(params, want) {
+		t.Fatalf("\nwant params %#v\ngot  params %#v", want, params)
+	}
+
+	where, score, params, err = driver.NewSQLServerDriver().RenderScoredParam(expr.OR(
+		expr.BOOST(expr.Eq("title", "go"), 2),
+		expr.Eq("body", "go"),
+	))
+	if err != nil {
+		t.Fatalf("unexpected error rendering expression: %v", err)
+	}
+	if want := `([title] = @p1) OR ([body] = @p2)`; where != want {
+		t.Fatalf("\nwant where %s\ngot  where %s", want, where)
+	}
+	if want := `CASE WHEN [title] = @p3 THEN 2 ELSE 0 END + CASE WHEN [body] = @p4 THEN 1 ELSE 0 END`; score != want {
+		t.Fatalf("\nwant score %s\ngot  score %s", want, score)
+	}
+	if want := []any{"go", "go", "go", "go"}; !reflect.DeepEqual(params, want) {
+		t.Fatalf("\nwant params %#v\ngot  params %#v", want, params)
+	}
+
+	// the plain render functions still refuse boosts rather than silently dropping them
+	_, err = ToPostgres(input)
+	if err == nil || !strings.Contains(err.Error(), "unable to render operator [BOOST]") {
+		t.Fatalf("expected boost render error from ToPostgres, got: %v", err)
+	}
+}
+
+func TestScoredLeavesExpressionUntouched(t *testing.T) {
+	e, err := Parse("title:go^2 OR body:go")
+	if err != nil {
+		t.Fatalf("unable to parse expression: %v", err)
+	}
+	before := e.String()
+	if _, _, err := postgres.RenderScored(e); err != nil {
+		t.Fatalf("unexpected error rendering expression: %v", err)
+	}
+	if after := e.String(); after != before {
+		t.Fatalf("RenderScored modified the expression\nbefore %s\nafter  %s", before, after)
+	}
+}
@@ -0,0 +1,172 @@
+package lucene
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSQLServerSQLEndToEnd(t *testing.T) {
+	type tc struct {
+		input        string
+		want         string
+		defaultField string
+		err          string
+	}
+
+	tcs := map[string]tc{
+		"basic_equal": {
+			input: "a:b",
+			want:  "[a] = N'b'",
+		},
+		"basic_equal_with_number": {
+			input: "a:5",
+			want:  "[a] = 5",
+		},
+		"basic_greater_less_eq_with_number": {
+			input: "a:<=22 AND b:>=33",
+			want:  "([a] <= 22) AND ([b] >= 33)",
+		},
+		"basic_wild_equal_with_*": {
+			input: "a:b*",
+			want:  "[a] LIKE N'b%'",
+		},
+		"basic_wild_equal_with_?": {
+			input: "a:b?z",
+			want:  "[a] LIKE N'b_z'",
+		},
+		"wild_with_like_metacharacters": {
+			input: "a:100%_*",
+			want:  "[a] LIKE N'100[%][_]%'",
+		},
+		"standalone_wild": {
+			input: "a:*",
+			want:  "[a] IS NOT NULL",
+		},
+		"regexp_rejected": {
+			input: "a:/b.*/",
+			err:   "SQL Server has no native regular expressions",
+		},
+		"basic_inclusive_range": {
+			input: "a:[1 TO 5]",
+			want:  "[a] >= 1 AND [a] <= 5",
+		},
+		"string_range": {
+			input: "a:[aaa TO bbb]",
+			want:  "[a] BETWEEN N'aaa' AND N'bbb'",
+		},
+		"open_range": {
+			input: "a:{* TO 5}",
+			want:  "[a] < 5",
+		},
+		"phrase_with_quote": {
+			input: `a:"it's here"`,
+			want:  "[a] = N'it''s here'",
+		},
+		"in_list": {
+			input: "a:(b OR c)",
+			want:  "[a] IN (N'b', N'c')",
+		},
+		"null": {
+			input: "a:null",
+			want:  "[a] IS NULL",
+		},
+		"not": {
+			input: "NOT a:b",
+			want:  "NOT([a] = N'b')",
+		},
+		"must_not": {
+			input: "a:b -c:d",
+			want:  "([a] = N'b') AND (NOT([c] = N'd'))",
+		},
+		"default_field": {
+			input:        "foo",
+			defaultField: "a",
+			want:         "[a] = N'foo'",
+		},
+		"fuzzy_soundex": {
+			input: "a:foo~",
+			want:  "SOUNDEX([a]) = SOUNDEX(N'foo')",
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			got, err := ToSQLServer(tc.input, WithDefaultField(tc.defaultField))
+			if err != nil {
+				if tc.err != "" && strings.Contains(err.Error(), tc.err) {
+					return
+				}
+				t.Fatalf("unexpected error rendering expression: %v", err)
+			}
+
+			if tc.err != "" {
+				t.Fatalf("\nexpected error [%s]\ngot: %s", tc.err, got)
+			}
+
+			if got != tc.want {
+				t.Fatalf("\nwant %s\ngot  %s\n", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestSQLServerParameterizedSQLEndToEnd(t *testing.T) {
+	type tc struct {
+		input      string
+		wantStr    string
+		wantParams []any
+		err        string
+	}
+
+	tcs := map[string]tc{
+		"basic_equal": {
+			input:      "a:b",
+			wantStr:    "[a] = @p1",
+			wantParams: []any{"b"},
+		},
+		"numbered_in_order": {
+			input:      "a:b AND c:[1 TO 5] AND d:(x OR y)",
+			wantStr:    "(([a] = @p1) AND ([c] >= @p2 AND [c] <= @p3)) AND ([d] IN (@p4, @p5))",
+			wantParams: []any{"b", 1, 5, "x", "y"},
+		},
+		"wildcard": {
+			input:      "a:b_*",
+			wantStr:    "[a] LIKE @p1",
+			wantParams: []any{"b[_]%"},
+		},
+		"standalone_wild": {
+			input:      "a:*",
+			wantStr:    "[a] IS NOT NULL",
+			wantParams: []any{},
+		},
+		"regexp_rejected": {
+			input: "a:/b.*/",
+			err:   "SQL Server has no native regular expressions",
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			gotStr, gotParams, err := ToParameterizedSQLServer(tc.input)
+			if err != nil {
+				if tc.err != "" && strings.Contains(err.Error(), tc.err) {
+					return
+				}
+				t.Fatalf("unexpected error rendering expression: %v", err)
+			}
+
+			if tc.err != "" {
+				t.Fatalf("\nexpected error [%s]\ngot: %s", tc.err, gotStr)
+			}
+
+			if gotStr != tc.wantStr {
+				t.Fatalf("\nwant %s\ngot  %s\n", tc.wantStr, gotStr)
+			}
+
+			if len(gotParams) != len(tc.wantParams) || (len(gotParams) > 0 && !reflect.DeepEqual(gotParams, tc.wantParams)) {
+				t.Fatalf("expected params %v, got %v", tc.wantParams, gotParams)
+			}
+		})
+	}
+}